@@ -0,0 +1,267 @@
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BoundFields are fields bound to a logger by Log.With.
+// They are never modified, so formatters may cache their encoded form.
+type BoundFields struct {
+	fields Fields
+	cache  sync.Map
+}
+
+func (that *BoundFields) Fields() Fields {
+	return that.fields
+}
+
+// Encoded returns the form of fields encoded by encode, that is cached by key.
+// Formatters use themselves as the key.
+func (that *BoundFields) Encoded(key interface{}, encode func(fields Fields) ([]byte, error)) ([]byte, error) {
+	if cached, ok := that.cache.Load(key); ok {
+		return cached.([]byte), nil
+	}
+
+	encoded, err := encode(that.fields)
+	if err != nil {
+		return nil, err
+	}
+
+	that.cache.Store(key, encoded)
+	return encoded, nil
+}
+
+// BoundLogger is a logger with pre-bound fields returned by Log.With.
+// It is safe for concurrent use and is meant to be created once per scope
+// (e.g. request) and reused, so logging calls do not copy the fields.
+type BoundLogger struct {
+	log   *Log
+	bound *BoundFields
+}
+
+// With returns the logger with fields bound on top of the fields of the logger.
+func (that *Log) With(fields Fields) *BoundLogger {
+	data := make(Fields, len(fields)+1)
+	if that.name != "" {
+		data[FieldKeyLogger] = that.name
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+
+	return &BoundLogger{
+		log:   that,
+		bound: &BoundFields{fields: data},
+	}
+}
+
+// With returns the logger with fields bound on top of the fields of the logger.
+func (that *BoundLogger) With(fields Fields) *BoundLogger {
+	data := make(Fields, len(that.bound.fields)+len(fields))
+	for k, v := range that.bound.fields {
+		data[k] = v
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+
+	return &BoundLogger{
+		log:   that.log,
+		bound: &BoundFields{fields: data},
+	}
+}
+
+func (that *BoundLogger) WithField(key string, value interface{}) LoggerEntry {
+	entry := that.newEntry()
+	defer that.log.freeEntry(entry)
+	return entry.WithField(key, value)
+}
+
+func (that *BoundLogger) WithFields(fields Fields) LoggerEntry {
+	entry := that.newEntry()
+	defer that.log.freeEntry(entry)
+	return entry.WithFields(fields)
+}
+
+func (that *BoundLogger) WithError(err error) LoggerEntry {
+	entry := that.newEntry()
+	defer that.log.freeEntry(entry)
+	return entry.WithError(err)
+}
+
+func (that *BoundLogger) WithTime(t time.Time) *Entry {
+	entry := that.newEntry()
+	defer that.log.freeEntry(entry)
+	return entry.WithTime(t)
+}
+
+func (that *BoundLogger) PanicFn(ctx context.Context, fn LogFunction) {
+	that.LogFn(ctx, PanicLevel, fn)
+}
+
+func (that *BoundLogger) FatalFn(ctx context.Context, fn LogFunction) {
+	that.LogFn(ctx, FatalLevel, fn)
+}
+
+func (that *BoundLogger) ErrorFn(ctx context.Context, fn LogFunction) {
+	that.LogFn(ctx, ErrorLevel, fn)
+}
+
+func (that *BoundLogger) WarningFn(ctx context.Context, fn LogFunction) {
+	that.LogFn(ctx, WarnLevel, fn)
+}
+
+func (that *BoundLogger) InfoFn(ctx context.Context, fn LogFunction) {
+	that.LogFn(ctx, InfoLevel, fn)
+}
+
+func (that *BoundLogger) DebugFn(ctx context.Context, fn LogFunction) {
+	that.LogFn(ctx, DebugLevel, fn)
+}
+
+func (that *BoundLogger) TraceFn(ctx context.Context, fn LogFunction) {
+	that.LogFn(ctx, TraceLevel, fn)
+}
+
+func (that *BoundLogger) Panicf(ctx context.Context, format string, args ...interface{}) {
+	that.Logf(ctx, PanicLevel, format, args...)
+}
+
+func (that *BoundLogger) Fatalf(ctx context.Context, format string, args ...interface{}) {
+	that.Logf(ctx, FatalLevel, format, args...)
+}
+
+func (that *BoundLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	that.Logf(ctx, ErrorLevel, format, args...)
+}
+
+func (that *BoundLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	that.Logf(ctx, WarnLevel, format, args...)
+}
+
+func (that *BoundLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	that.Logf(ctx, InfoLevel, format, args...)
+}
+
+func (that *BoundLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	that.Logf(ctx, DebugLevel, format, args...)
+}
+
+func (that *BoundLogger) Tracef(ctx context.Context, format string, args ...interface{}) {
+	that.Logf(ctx, TraceLevel, format, args...)
+}
+
+func (that *BoundLogger) Panic(ctx context.Context, args ...interface{}) {
+	that.Log(ctx, PanicLevel, args...)
+}
+
+func (that *BoundLogger) Fatal(ctx context.Context, args ...interface{}) {
+	that.Log(ctx, FatalLevel, args...)
+}
+
+func (that *BoundLogger) Error(ctx context.Context, args ...interface{}) {
+	that.Log(ctx, ErrorLevel, args...)
+}
+
+func (that *BoundLogger) Warning(ctx context.Context, args ...interface{}) {
+	that.Log(ctx, WarnLevel, args...)
+}
+
+func (that *BoundLogger) Info(ctx context.Context, args ...interface{}) {
+	that.Log(ctx, InfoLevel, args...)
+}
+
+func (that *BoundLogger) Debug(ctx context.Context, args ...interface{}) {
+	that.Log(ctx, DebugLevel, args...)
+}
+
+func (that *BoundLogger) Trace(ctx context.Context, args ...interface{}) {
+	that.Log(ctx, TraceLevel, args...)
+}
+
+func (that *BoundLogger) LogFn(ctx context.Context, level Level, fn LogFunction) {
+	if that.log.IsLevelEnabled(level) {
+		entry := that.newEntry()
+		defer that.log.freeEntry(entry)
+
+		entry.Level = level
+		fn(ctx, entry)
+	} else if level == FatalLevel {
+		that.log.Exit(ctx, 1)
+	}
+}
+
+func (that *BoundLogger) Logf(ctx context.Context, level Level, format string, args ...interface{}) {
+	if that.log.IsLevelEnabled(level) {
+		entry := that.newEntry()
+		defer that.log.freeEntry(entry)
+
+		entry.Logf(ctx, level, format, args...)
+	} else if level == FatalLevel {
+		that.log.Exit(ctx, 1)
+	}
+}
+
+func (that *BoundLogger) Log(ctx context.Context, level Level, args ...interface{}) {
+	if that.log.IsLevelEnabled(level) {
+		entry := that.newEntry()
+		defer that.log.freeEntry(entry)
+
+		entry.Log(ctx, level, args...)
+	} else if level == FatalLevel {
+		that.log.Exit(ctx, 1)
+	}
+}
+
+func (that *BoundLogger) PanicFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, PanicLevel, msg, fields...)
+}
+
+func (that *BoundLogger) FatalFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, FatalLevel, msg, fields...)
+}
+
+func (that *BoundLogger) ErrorFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, ErrorLevel, msg, fields...)
+}
+
+func (that *BoundLogger) WarningFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, WarnLevel, msg, fields...)
+}
+
+func (that *BoundLogger) InfoFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, InfoLevel, msg, fields...)
+}
+
+func (that *BoundLogger) DebugFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, DebugLevel, msg, fields...)
+}
+
+func (that *BoundLogger) TraceFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, TraceLevel, msg, fields...)
+}
+
+// LogFields logs the message with typed fields, that are not boxed into Fields.
+func (that *BoundLogger) LogFields(ctx context.Context, level Level, msg string, fields ...Field) {
+	if that.log.IsLevelEnabled(level) {
+		entry := that.newEntry()
+		defer that.log.freeEntry(entry)
+
+		entry.LogFields(ctx, level, msg, fields...)
+	} else if level == FatalLevel {
+		that.log.Exit(ctx, 1)
+	}
+}
+
+func (that *BoundLogger) IsLevelEnabled(level Level) bool {
+	return that.log.IsLevelEnabled(level)
+}
+
+func (that *BoundLogger) newEntry() *Entry {
+	entry := that.log.newEntry()
+	entry.Data = that.bound.fields
+	entry.Bound = that.bound
+	return entry
+}
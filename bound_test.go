@@ -0,0 +1,75 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBoundLogger(t *testing.T) {
+	exporter := &myExporter{}
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	request := logger.Named("http").With(Fields{"request_id": "r1"})
+	user := request.With(Fields{"user_id": 42})
+
+	request.Info(ctx, "Hello, World!")
+	assert.Equal(t, Fields{FieldKeyLogger: "http", "request_id": "r1"}, exporter.entry.Data)
+	assert.NotNil(t, exporter.entry.Bound)
+
+	user.WithField("key", "value").Warning(ctx, "Hello, World!")
+	assert.Equal(t, Fields{FieldKeyLogger: "http", "request_id": "r1", "user_id": 42, "key": "value"}, exporter.entry.Data)
+	assert.Nil(t, exporter.entry.Bound)
+
+	user.Debug(ctx, "skipped")
+	assert.Equal(t, WarnLevel, exporter.entry.Level)
+}
+
+func TestBoundLoggerHooks(t *testing.T) {
+	exporter := &myExporter{}
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		WithHook(HookFunc(func(ctx context.Context, entry *Entry) error {
+			entry.Data["hook"] = true
+			return nil
+		})).
+		Build()
+	require.NoError(t, err)
+
+	bound := logger.With(Fields{"key": "value"})
+	bound.Info(context.Background(), "Hello, World!")
+
+	assert.Equal(t, Fields{"key": "value", "hook": true}, exporter.entry.Data)
+	assert.Equal(t, Fields{"key": "value"}, bound.bound.Fields())
+}
+
+var benchmarkFields = Fields{"request_id": "r1", "user_id": 42, "path": "/api"}
+
+func BenchmarkWithFields(b *testing.B) {
+	logger := NewDummyLogger()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.WithFields(benchmarkFields).Info(ctx, "Hello, World!")
+	}
+}
+
+func BenchmarkWith(b *testing.B) {
+	logger := NewDummyLogger().With(benchmarkFields)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info(ctx, "Hello, World!")
+	}
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const maximumCallerDepth = 32
+
+var (
+	wrapperPrefixes     []string
+	wrapperPrefixesOnce sync.Once
+)
+
+// getCaller returns the first frame outside of the logger wrappers
+// (Log, Entry, Piece and SlogHandler methods), skipping extra frames on top.
+func getCaller(skip int) *runtime.Frame {
+	wrapperPrefixesOnce.Do(initWrapperPrefixes)
+
+	pcs := make([]uintptr, maximumCallerDepth)
+	depth := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:depth])
+
+	for {
+		frame, more := frames.Next()
+		if !isWrapperFrame(frame.Function) {
+			if skip == 0 {
+				return &frame
+			}
+			skip--
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// getStack returns frames below the logger wrappers, skipping extra frames on top.
+func getStack(skip int) StackTrace {
+	wrapperPrefixesOnce.Do(initWrapperPrefixes)
+
+	pcs := make([]uintptr, maximumStackDepth)
+	depth := runtime.Callers(2, pcs)
+	stack := NewStackTrace(pcs[:depth])
+
+	for i, frame := range stack {
+		if isWrapperFrame(frame.Function) {
+			continue
+		}
+		if skip == 0 {
+			return stack[i:]
+		}
+		skip--
+	}
+	return nil
+}
+
+// getCallerByPC returns the frame of the program counter.
+func getCallerByPC(pc uintptr) *runtime.Frame {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+	if frame.PC == 0 {
+		return nil
+	}
+	return &frame
+}
+
+func isWrapperFrame(function string) bool {
+	for _, prefix := range wrapperPrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func initWrapperPrefixes() {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	frame, _ := runtime.CallersFrames(pcs).Next()
+
+	// frame.Function is "<package path>.initWrapperPrefixes"
+	pkg := frame.Function[:strings.LastIndexByte(frame.Function, '.')]
+	for _, receiver := range []string{"Log", "BoundLogger", "Entry", "Piece", "SlogHandler"} {
+		wrapperPrefixes = append(wrapperPrefixes, pkg+".(*"+receiver+").")
+	}
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"runtime"
+	"testing"
+)
+
+func TestReportCaller(t *testing.T) {
+	exporter := &myExporter{}
+
+	newLogger := func(skip int) *Log {
+		logger, err := NewBuilder().
+			WithExporter(exporter).
+			WithReportCaller(true).
+			WithCallerSkip(skip).
+			Build()
+		require.NoError(t, err)
+		return logger
+	}
+
+	ctx := context.Background()
+	logger := newLogger(0)
+	helper := func(logger *Log) {
+		logger.Info(ctx, "helper")
+	}
+
+	assertCaller := func(expectedLine int) {
+		t.Helper()
+		require.NotNil(t, exporter.entry.Caller)
+		assert.Equal(t, "github.com/adverax/log.TestReportCaller", exporter.entry.Caller.Function)
+		assert.Equal(t, expectedLine, exporter.entry.Caller.Line)
+	}
+
+	logger.Info(ctx, "direct")
+	assertCaller(currentLine() - 1)
+
+	logger.WithField("key", "value").Warningf(ctx, "entry %d", 1)
+	assertCaller(currentLine() - 1)
+
+	slog.New(NewSlogHandler(logger)).Info("slog")
+	assertCaller(currentLine() - 1)
+
+	bound := logger.With(Fields{"key": "value"})
+	bound.Info(ctx, "bound")
+	assertCaller(currentLine() - 1)
+
+	bound.InfoFields(ctx, "bound fields", String("key", "value"))
+	assertCaller(currentLine() - 1)
+
+	bound.WithField("extra", 1).Errorf(ctx, "bound entry %d", 1)
+	assertCaller(currentLine() - 1)
+
+	helper(newLogger(1))
+	assertCaller(currentLine() - 1)
+}
+
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"runtime"
 	"time"
 )
 
@@ -17,8 +18,20 @@ type Entry struct {
 	Time    time.Time
 	Level   Level
 	Message string
-	Buffer  *bytes.Buffer
-	LogErr  string
+	// Template is the format of the message passed to Logf, empty otherwise.
+	Template string
+	Buffer   *bytes.Buffer
+	LogErr   string
+	// Caller is the calling frame, when reporting of caller is enabled.
+	Caller *runtime.Frame
+	// Stack is the stack trace, when capturing of stack is enabled for the level.
+	Stack StackTrace
+	// Bound refers to the fields bound by Log.With, while Data is exactly them.
+	// Data must not be modified in this case.
+	Bound *BoundFields
+	// TypedFields are fields passed to LogFields. They take precedence over Data.
+	// The slice is owned by the logger pool, so it is copied by Snapshot.
+	TypedFields []Field
 }
 
 func NewEntry(logger *Log) *Entry {
@@ -30,12 +43,18 @@ func NewEntry(logger *Log) *Entry {
 
 func (that *Entry) clear() {
 	that.Logger = nil
-	that.Data = map[string]interface{}{}
+	that.Data = nil
 	that.Time = time.Time{}
 	that.Level = 0
 	that.Message = ""
+	that.Template = ""
 	that.Buffer = nil
 	that.LogErr = ""
+	that.Caller = nil
+	that.Stack = nil
+	that.Bound = nil
+	clear(that.TypedFields)
+	that.TypedFields = that.TypedFields[:0]
 }
 
 func (that *Entry) clone() *Entry {
@@ -44,10 +63,67 @@ func (that *Entry) clone() *Entry {
 	newEntry.Time = that.Time
 	newEntry.Level = that.Level
 	newEntry.Message = that.Message
+	newEntry.Template = that.Template
 	newEntry.Logger = that.Logger
+	newEntry.Caller = that.Caller
+	newEntry.Stack = that.Stack
+	newEntry.Bound = that.Bound
+	newEntry.TypedFields = append(newEntry.TypedFields, that.TypedFields...)
 	return newEntry
 }
 
+// HasCaller reports whether the entry contains the calling frame.
+func (that *Entry) HasCaller() bool {
+	return that.Caller != nil
+}
+
+// Snapshot returns a copy of the entry that is not owned by the logger pool,
+// so it remains valid after Export returns.
+func (that *Entry) Snapshot() *Entry {
+	return &Entry{
+		Logger:      that.Logger,
+		Data:        that.Data.Clone(),
+		Time:        that.Time,
+		Level:       that.Level,
+		Message:     that.Message,
+		Template:    that.Template,
+		LogErr:      that.LogErr,
+		Caller:      that.Caller,
+		Stack:       that.Stack,
+		TypedFields: append([]Field(nil), that.TypedFields...),
+	}
+}
+
+// Fields returns Data merged with TypedFields.
+// Data is returned as is, when there are no typed fields.
+func (that *Entry) Fields() Fields {
+	if len(that.TypedFields) == 0 {
+		return that.Data
+	}
+
+	data := make(Fields, len(that.Data)+len(that.TypedFields))
+	for k, v := range that.Data {
+		data[k] = v
+	}
+	for _, field := range that.TypedFields {
+		data[field.Key] = field.Value()
+	}
+	return data
+}
+
+// ErrorValue returns the error of the entry stored with key ErrorKey.
+func (that *Entry) ErrorValue() error {
+	for i := len(that.TypedFields) - 1; i >= 0; i-- {
+		if field := that.TypedFields[i]; field.Key == ErrorKey {
+			err, _ := field.Interface.(error)
+			return err
+		}
+	}
+
+	err, _ := that.Data[ErrorKey].(error)
+	return err
+}
+
 func (that *Entry) WithField(key string, value interface{}) LoggerEntry {
 	return that.withFields(Fields{key: value})
 }
@@ -143,37 +219,161 @@ func (that *Entry) Trace(ctx context.Context, args ...interface{}) {
 	that.Log(ctx, TraceLevel, args...)
 }
 
+func (that *Entry) PanicFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, PanicLevel, msg, fields...)
+}
+
+func (that *Entry) FatalFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, FatalLevel, msg, fields...)
+}
+
+func (that *Entry) ErrorFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, ErrorLevel, msg, fields...)
+}
+
+func (that *Entry) WarningFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, WarnLevel, msg, fields...)
+}
+
+func (that *Entry) InfoFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, InfoLevel, msg, fields...)
+}
+
+func (that *Entry) DebugFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, DebugLevel, msg, fields...)
+}
+
+func (that *Entry) TraceFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, TraceLevel, msg, fields...)
+}
+
+// LogFields logs the message with typed fields, that are not boxed into Fields.
+func (that *Entry) LogFields(ctx context.Context, level Level, msg string, fields ...Field) {
+	if that.Logger.IsLevelEnabled(level) {
+		that.log(ctx, level, "", msg, fields...)
+	} else if level == FatalLevel {
+		that.Logger.Exit(ctx, 1)
+	}
+}
+
 func (that *Entry) Logf(ctx context.Context, level Level, format string, args ...interface{}) {
 	if that.Logger.IsLevelEnabled(level) {
-		that.log(ctx, level, fmt.Sprintf(format, args...))
+		that.log(ctx, level, format, fmt.Sprintf(format, args...))
+	} else if level == FatalLevel {
+		that.Logger.Exit(ctx, 1)
 	}
 }
 
 func (that *Entry) Log(ctx context.Context, level Level, args ...interface{}) {
 	if that.Logger.IsLevelEnabled(level) {
-		that.log(ctx, level, fmt.Sprint(args...))
+		that.log(ctx, level, "", fmt.Sprint(args...))
+	} else if level == FatalLevel {
+		that.Logger.Exit(ctx, 1)
 	}
 }
 
-func (that *Entry) log(ctx context.Context, level Level, msg string) {
+func (that *Entry) log(ctx context.Context, level Level, template, msg string, fields ...Field) {
 	entry := that.clone()
 	defer that.Logger.freeEntry(entry)
 
-	entry.prepare(level, msg)
+	entry.TypedFields = append(entry.TypedFields, fields...)
+	entry.prepare(level, template, msg)
+	entry.extract(ctx)
+	if entry.Logger.reportCaller && entry.Caller == nil {
+		entry.Caller = getCaller(entry.Logger.callerSkip)
+	}
+	if entry.Logger.captureStack && entry.Level <= entry.Logger.stackLevel && entry.Stack == nil {
+		entry.Stack = entry.stackOfError()
+		if entry.Stack == nil {
+			entry.Stack = getStack(entry.Logger.callerSkip)
+		}
+	}
+	if entry.Logger.hooks.Has(entry.Level) {
+		entry.ownData()
+	}
 	entry.fire(ctx)
 	entry.Logger.exporter.Export(ctx, entry)
 
-	if entry.Level <= PanicLevel {
+	switch entry.Level {
+	case PanicLevel:
 		panic(entry)
+	case FatalLevel:
+		entry.Logger.Exit(ctx, 1)
+	}
+}
+
+// extract merges the name of the logger and fields from the context.
+// Fields of the entry take precedence.
+// Data may be shared with the parent entry, so it is replaced instead of modified.
+func (that *Entry) extract(ctx context.Context) {
+	var data Fields
+	if _, ok := that.Data[FieldKeyLogger]; !ok && that.Logger.name != "" {
+		data = make(Fields, len(that.Data)+1)
+		data[FieldKeyLogger] = that.Logger.name
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for _, extractor := range that.Logger.extractors {
+		fields := extractor.Extract(ctx)
+		if len(fields) == 0 {
+			continue
+		}
+		if data == nil {
+			data = make(Fields, len(fields)+len(that.Data))
+		}
+		for k, v := range fields {
+			data[k] = v
+		}
+	}
+
+	if data == nil {
+		return
+	}
+
+	for k, v := range that.Data {
+		data[k] = v
+	}
+	that.Data = data
+	that.Bound = nil
+}
+
+// ownData makes Data safe for modification by hooks.
+// Typed fields are moved into Data, so hooks see all fields of the entry.
+func (that *Entry) ownData() {
+	if len(that.TypedFields) != 0 {
+		that.Data = that.Fields()
+		that.Bound = nil
+		clear(that.TypedFields)
+		that.TypedFields = that.TypedFields[:0]
+		return
+	}
+
+	switch {
+	case that.Bound != nil:
+		that.Data = that.Data.Clone()
+		that.Bound = nil
+	case that.Data == nil:
+		that.Data = make(Fields)
+	}
+}
+
+func (that *Entry) stackOfError() StackTrace {
+	if err := that.ErrorValue(); err != nil {
+		return StackOf(err)
 	}
+	return nil
 }
 
-func (that *Entry) prepare(level Level, msg string) {
+func (that *Entry) prepare(level Level, template, msg string) {
 	if that.Time.IsZero() {
 		that.Time = time.Now()
 	}
 
 	that.Level = level
+	that.Template = template
 	that.Message = msg
 }
 
@@ -8,6 +8,32 @@ type Exporter interface {
 	Export(ctx context.Context, entry *Entry)
 }
 
+// Flusher is implemented by exporters and hooks that buffer entries.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Closer is implemented by exporters and hooks that hold resources.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Flush flushes target if it implements Flusher.
+func Flush(ctx context.Context, target interface{}) error {
+	if flusher, ok := target.(Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
+
+// Close closes target if it implements Closer.
+func Close(ctx context.Context, target interface{}) error {
+	if closer, ok := target.(Closer); ok {
+		return closer.Close(ctx)
+	}
+	return nil
+}
+
 type dummyExporter struct{}
 
 func (that *dummyExporter) Export(ctx context.Context, entry *Entry) {
@@ -0,0 +1,76 @@
+package asyncExporter
+
+import (
+	"errors"
+	"github.com/adverax/log"
+)
+
+type Builder struct {
+	exporter *Exporter
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		exporter: &Exporter{
+			queueSize: 1024,
+			workers:   1,
+			policy:    OverflowPolicyBlock,
+			dropLevel: log.WarnLevel,
+		},
+	}
+}
+
+func (that *Builder) WithExporter(exporter log.Exporter) *Builder {
+	that.exporter.exporter = exporter
+	return that
+}
+
+func (that *Builder) WithQueueSize(queueSize int) *Builder {
+	that.exporter.queueSize = queueSize
+	return that
+}
+
+func (that *Builder) WithWorkers(workers int) *Builder {
+	that.exporter.workers = workers
+	return that
+}
+
+func (that *Builder) WithOverflowPolicy(policy OverflowPolicy) *Builder {
+	that.exporter.policy = policy
+	return that
+}
+
+// WithDropLevel sets the least severe level that is still enqueued
+// by OverflowPolicyDropBelowLevel when the queue is full.
+func (that *Builder) WithDropLevel(level log.Level) *Builder {
+	that.exporter.dropLevel = level
+	return that
+}
+
+func (that *Builder) Build() (*Exporter, error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+
+	that.exporter.start()
+	return that.exporter, nil
+}
+
+func (that *Builder) checkRequiredFields() error {
+	if that.exporter.exporter == nil {
+		return ErrRequiredFieldExporter
+	}
+	if that.exporter.queueSize <= 0 {
+		return ErrInvalidQueueSize
+	}
+	if that.exporter.workers <= 0 {
+		return ErrInvalidWorkers
+	}
+	return nil
+}
+
+var (
+	ErrRequiredFieldExporter = errors.New("exporter is required")
+	ErrInvalidQueueSize      = errors.New("queue size must be positive")
+	ErrInvalidWorkers        = errors.New("workers must be positive")
+)
@@ -0,0 +1,228 @@
+package asyncExporter
+
+import (
+	"context"
+	"github.com/adverax/enums"
+	"github.com/adverax/log"
+	"sync"
+	"sync/atomic"
+)
+
+type OverflowPolicy int
+
+func (that OverflowPolicy) String() string {
+	return OverflowPolicies.DecodeOrDefault(that, "unknown")
+}
+
+const (
+	// OverflowPolicyBlock waits until the queue has free space.
+	OverflowPolicyBlock OverflowPolicy = iota
+	// OverflowPolicyDropNewest discards the entry being exported.
+	OverflowPolicyDropNewest
+	// OverflowPolicyDropOldest discards the oldest queued entry.
+	OverflowPolicyDropOldest
+	// OverflowPolicyDropBelowLevel discards entries less severe than the drop level
+	// and blocks for the others.
+	OverflowPolicyDropBelowLevel
+)
+
+var OverflowPolicies = enums.New[OverflowPolicy](
+	map[OverflowPolicy]string{
+		OverflowPolicyBlock:          "block",
+		OverflowPolicyDropNewest:     "drop-newest",
+		OverflowPolicyDropOldest:     "drop-oldest",
+		OverflowPolicyDropBelowLevel: "drop-below-level",
+	},
+)
+
+type Stats struct {
+	Pending  int
+	Exported uint64
+	Dropped  uint64
+}
+
+type task struct {
+	ctx   context.Context
+	entry *log.Entry
+}
+
+// Exporter exports entries on background workers through a bounded queue.
+type Exporter struct {
+	exporter  log.Exporter
+	queueSize int
+	workers   int
+	policy    OverflowPolicy
+	dropLevel log.Level
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []task
+	head     int
+	size     int
+	pending  int
+	idle     chan struct{}
+	closed   bool
+	done     chan struct{}
+
+	closeOnce sync.Once
+
+	exported atomic.Uint64
+	dropped  atomic.Uint64
+}
+
+func (that *Exporter) start() {
+	that.queue = make([]task, that.queueSize)
+	that.notEmpty = sync.NewCond(&that.mu)
+	that.notFull = sync.NewCond(&that.mu)
+	that.idle = make(chan struct{})
+	close(that.idle)
+	that.done = make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(that.workers)
+	for i := 0; i < that.workers; i++ {
+		go func() {
+			defer wg.Done()
+			that.work()
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(that.done)
+	}()
+}
+
+func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	for that.size == len(that.queue) && !that.closed {
+		switch that.policy {
+		case OverflowPolicyDropNewest:
+			that.dropped.Add(1)
+			return
+		case OverflowPolicyDropOldest:
+			that.pop()
+			that.release()
+			that.dropped.Add(1)
+		case OverflowPolicyDropBelowLevel:
+			if entry.Level > that.dropLevel {
+				that.dropped.Add(1)
+				return
+			}
+			that.notFull.Wait()
+		default:
+			that.notFull.Wait()
+		}
+	}
+
+	if that.closed {
+		that.dropped.Add(1)
+		return
+	}
+
+	that.push(task{ctx: context.WithoutCancel(ctx), entry: entry.Snapshot()})
+}
+
+// Flush waits until all queued entries are exported and flushes the wrapped exporter.
+func (that *Exporter) Flush(ctx context.Context) error {
+	that.mu.Lock()
+	idle := that.idle
+	that.mu.Unlock()
+
+	select {
+	case <-idle:
+		return log.Flush(ctx, that.exporter)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting entries, waits until queued entries are exported
+// and closes the wrapped exporter. The wrapped exporter is closed only once.
+func (that *Exporter) Close(ctx context.Context) error {
+	that.mu.Lock()
+	that.closed = true
+	that.notEmpty.Broadcast()
+	that.notFull.Broadcast()
+	that.mu.Unlock()
+
+	select {
+	case <-that.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var err error
+	that.closeOnce.Do(func() {
+		err = log.Close(ctx, that.exporter)
+	})
+	return err
+}
+
+func (that *Exporter) Dropped() uint64 {
+	return that.dropped.Load()
+}
+
+func (that *Exporter) Stats() Stats {
+	that.mu.Lock()
+	pending := that.pending
+	that.mu.Unlock()
+
+	return Stats{
+		Pending:  pending,
+		Exported: that.exported.Load(),
+		Dropped:  that.dropped.Load(),
+	}
+}
+
+func (that *Exporter) work() {
+	for {
+		that.mu.Lock()
+		for that.size == 0 && !that.closed {
+			that.notEmpty.Wait()
+		}
+		if that.size == 0 {
+			that.mu.Unlock()
+			return
+		}
+		t := that.pop()
+		that.mu.Unlock()
+
+		that.exporter.Export(t.ctx, t.entry)
+		that.exported.Add(1)
+
+		that.mu.Lock()
+		that.release()
+		that.mu.Unlock()
+	}
+}
+
+func (that *Exporter) push(t task) {
+	that.queue[(that.head+that.size)%len(that.queue)] = t
+	that.size++
+	if that.pending == 0 {
+		that.idle = make(chan struct{})
+	}
+	that.pending++
+	that.notEmpty.Signal()
+}
+
+func (that *Exporter) pop() task {
+	t := that.queue[that.head]
+	that.queue[that.head] = task{}
+	that.head = (that.head + 1) % len(that.queue)
+	that.size--
+	that.notFull.Signal()
+	return t
+}
+
+// release marks one queued entry as finished.
+func (that *Exporter) release() {
+	that.pending--
+	if that.pending == 0 {
+		close(that.idle)
+	}
+}
@@ -0,0 +1,115 @@
+package asyncExporter
+
+import (
+	"context"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+type gateExporter struct {
+	sync.Mutex
+	started  chan struct{}
+	gate     chan struct{}
+	messages []string
+}
+
+func (that *gateExporter) Export(ctx context.Context, entry *log.Entry) {
+	that.started <- struct{}{}
+	<-that.gate
+	that.Lock()
+	defer that.Unlock()
+	that.messages = append(that.messages, entry.Message)
+}
+
+func TestExporter(t *testing.T) {
+	type Test struct {
+		name     string
+		policy   OverflowPolicy
+		levels   []log.Level
+		expected []string
+		dropped  uint64
+	}
+
+	tests := []Test{
+		{
+			name:     "drop newest",
+			policy:   OverflowPolicyDropNewest,
+			levels:   []log.Level{log.InfoLevel, log.InfoLevel, log.InfoLevel, log.InfoLevel},
+			expected: []string{"0", "1", "2"},
+			dropped:  1,
+		},
+		{
+			name:     "drop oldest",
+			policy:   OverflowPolicyDropOldest,
+			levels:   []log.Level{log.InfoLevel, log.InfoLevel, log.InfoLevel, log.InfoLevel},
+			expected: []string{"0", "2", "3"},
+			dropped:  1,
+		},
+		{
+			name:     "drop below level",
+			policy:   OverflowPolicyDropBelowLevel,
+			levels:   []log.Level{log.InfoLevel, log.InfoLevel, log.InfoLevel, log.DebugLevel},
+			expected: []string{"0", "1", "2"},
+			dropped:  1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target := &gateExporter{
+				started: make(chan struct{}, len(test.levels)),
+				gate:    make(chan struct{}),
+			}
+			exporter, err := NewBuilder().
+				WithExporter(target).
+				WithQueueSize(2).
+				WithOverflowPolicy(test.policy).
+				Build()
+			require.NoError(t, err)
+
+			logger, err := log.NewBuilder().
+				WithLevel(log.TraceLevel).
+				WithExporter(exporter).
+				Build()
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			logger.Info(ctx, "0")
+			<-target.started // worker holds the first entry
+			for i, level := range test.levels[1:] {
+				logger.Log(ctx, level, i+1)
+			}
+			close(target.gate)
+
+			require.NoError(t, exporter.Close(ctx))
+			assert.Equal(t, test.expected, target.messages)
+			assert.Equal(t, test.dropped, exporter.Dropped())
+		})
+	}
+}
+
+type closingExporter struct {
+	closed int
+}
+
+func (that *closingExporter) Export(ctx context.Context, entry *log.Entry) {}
+
+func (that *closingExporter) Close(ctx context.Context) error {
+	that.closed++
+	return nil
+}
+
+func TestExporterCloseOnce(t *testing.T) {
+	target := &closingExporter{}
+	exporter, err := NewBuilder().
+		WithExporter(target).
+		Build()
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.Close(context.Background()))
+	require.NoError(t, exporter.Close(context.Background()))
+	assert.Equal(t, 1, target.closed)
+}
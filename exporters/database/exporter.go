@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"github.com/adverax/log"
+	"sync"
 )
 
 type Translator func(interface{}) interface{}
@@ -17,17 +18,36 @@ type Exporter struct {
 	dataKey         string
 	timestampFormat string
 	fieldList       []string
+	mu              sync.RWMutex
+	closed          bool
 }
 
 func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
+	that.mu.RLock()
+	defer that.mu.RUnlock()
+
+	if that.closed {
+		return
+	}
+
 	data := that.makeData(entry)
 	fields := that.extractFields(data)
 	args := that.makeQueryArgs(fields)
 	_, _ = that.db.Exec(that.query, args...)
 }
 
+// Close waits for running exports and rejects further entries.
+// The database itself is owned by the caller and is not closed.
+func (that *Exporter) Close(ctx context.Context) error {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	that.closed = true
+	return nil
+}
+
 func (that *Exporter) makeData(entry *log.Entry) log.Fields {
-	data := entry.Data.Expand()
+	data := entry.Fields().Expand()
 
 	that.fieldMap.EncodePrefixFieldClashes(data)
 
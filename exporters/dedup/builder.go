@@ -0,0 +1,79 @@
+package dedupExporter
+
+import (
+	"errors"
+	"github.com/adverax/log"
+	"time"
+)
+
+type Builder struct {
+	exporter *Exporter
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		exporter: &Exporter{
+			mode:    ModeConsecutive,
+			window:  10 * time.Second,
+			now:     time.Now,
+			streaks: make(map[string]*streak),
+		},
+	}
+}
+
+func (that *Builder) WithExporter(exporter log.Exporter) *Builder {
+	that.exporter.exporter = exporter
+	return that
+}
+
+func (that *Builder) WithMode(mode Mode) *Builder {
+	that.exporter.mode = mode
+	return that
+}
+
+// WithWindow sets the longest duration of a streak. When it elapses, the summary
+// of repeats is exported and the next identical entry starts a new streak.
+// Zero window makes consecutive streaks unlimited.
+func (that *Builder) WithWindow(window time.Duration) *Builder {
+	that.exporter.window = window
+	return that
+}
+
+// WithFields sets fields, that are compared in addition to level and message.
+func (that *Builder) WithFields(fields ...string) *Builder {
+	that.exporter.fields = append(that.exporter.fields, fields...)
+	return that
+}
+
+func (that *Builder) WithClock(now func() time.Time) *Builder {
+	that.exporter.now = now
+	return that
+}
+
+func (that *Builder) Build() (*Exporter, error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+
+	that.exporter.start()
+	return that.exporter, nil
+}
+
+func (that *Builder) checkRequiredFields() error {
+	if that.exporter.exporter == nil {
+		return ErrRequiredFieldExporter
+	}
+	if that.exporter.now == nil {
+		return ErrRequiredFieldClock
+	}
+	if that.exporter.window < 0 || that.exporter.mode == ModeWindow && that.exporter.window == 0 {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+var (
+	ErrRequiredFieldExporter = errors.New("exporter is required")
+	ErrRequiredFieldClock    = errors.New("clock is required")
+	ErrInvalidWindow         = errors.New("window must be positive")
+)
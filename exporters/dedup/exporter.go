@@ -0,0 +1,252 @@
+package dedupExporter
+
+import (
+	"context"
+	"fmt"
+	"github.com/adverax/enums"
+	"github.com/adverax/log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Mode int
+
+func (that Mode) String() string {
+	return Modes.DecodeOrDefault(that, "unknown")
+}
+
+const (
+	// ModeConsecutive suppresses entries identical to the previous one.
+	ModeConsecutive Mode = iota
+	// ModeWindow suppresses entries identical to any entry seen within the window.
+	ModeWindow
+)
+
+var Modes = enums.New[Mode](
+	map[Mode]string{
+		ModeConsecutive: "consecutive",
+		ModeWindow:      "window",
+	},
+)
+
+const (
+	FieldKeyRepeated        = "repeated"
+	FieldKeyRepeatedMessage = "repeated_msg"
+)
+
+type streak struct {
+	key   string
+	entry *log.Entry
+	start time.Time
+	count uint64
+}
+
+// Exporter forwards the first of identical entries and suppresses repeats.
+// When the streak of repeats ends, it exports the single entry
+// "last message repeated N times" ("once" for a single repeat).
+// Entries are identical when level, message and selected fields are equal.
+type Exporter struct {
+	exporter log.Exporter
+	mode     Mode
+	window   time.Duration
+	fields   []string
+	now      func() time.Time
+
+	mu       sync.Mutex
+	last     *streak
+	streaks  map[string]*streak
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func (that *Exporter) start() {
+	that.stop = make(chan struct{})
+	that.done = make(chan struct{})
+	go that.run()
+}
+
+func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
+	key := that.signature(entry)
+	now := that.now()
+
+	that.mu.Lock()
+	var summary *log.Entry
+	var forward bool
+	if that.mode == ModeWindow {
+		summary, forward = that.observeWindow(key, entry, now)
+	} else {
+		summary, forward = that.observeConsecutive(key, entry, now)
+	}
+	that.mu.Unlock()
+
+	if summary != nil {
+		that.exporter.Export(ctx, summary)
+	}
+	if forward {
+		that.exporter.Export(ctx, entry)
+	}
+}
+
+// Flush exports summaries of pending streaks and flushes the wrapped exporter.
+// Streaks are not finished, so repeats are still suppressed.
+func (that *Exporter) Flush(ctx context.Context) error {
+	that.report(ctx, false)
+	return log.Flush(ctx, that.exporter)
+}
+
+// Close finishes all streaks and closes the wrapped exporter.
+func (that *Exporter) Close(ctx context.Context) error {
+	that.stopOnce.Do(func() {
+		close(that.stop)
+	})
+	select {
+	case <-that.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	that.report(ctx, false)
+	return log.Close(ctx, that.exporter)
+}
+
+func (that *Exporter) observeConsecutive(key string, entry *log.Entry, now time.Time) (*log.Entry, bool) {
+	if that.last != nil && that.last.key == key && !that.expired(that.last, now) {
+		that.last.count++
+		return nil, false
+	}
+
+	var summary *log.Entry
+	if that.last != nil {
+		summary = that.summarize(that.last, now)
+	}
+	that.last = newStreak(key, entry, now)
+	return summary, true
+}
+
+func (that *Exporter) observeWindow(key string, entry *log.Entry, now time.Time) (*log.Entry, bool) {
+	s, ok := that.streaks[key]
+	if ok && !that.expired(s, now) {
+		s.count++
+		return nil, false
+	}
+
+	var summary *log.Entry
+	if ok {
+		summary = that.summarize(s, now)
+	}
+	that.streaks[key] = newStreak(key, entry, now)
+	return summary, true
+}
+
+func (that *Exporter) run() {
+	defer close(that.done)
+
+	if that.window == 0 {
+		<-that.stop
+		return
+	}
+
+	ticker := time.NewTicker(that.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-that.stop:
+			return
+		case <-ticker.C:
+			that.report(context.Background(), true)
+		}
+	}
+}
+
+// report exports summaries of streaks. When expire is set, only streaks
+// with elapsed window are reported and finished.
+func (that *Exporter) report(ctx context.Context, expire bool) {
+	now := that.now()
+
+	that.mu.Lock()
+	var summaries []*log.Entry
+	visit := func(s *streak) bool {
+		if expire && !that.expired(s, now) {
+			return false
+		}
+		if summary := that.summarize(s, now); summary != nil {
+			summaries = append(summaries, summary)
+		}
+		return expire
+	}
+
+	if that.last != nil && visit(that.last) {
+		that.last = nil
+	}
+	for key, s := range that.streaks {
+		if visit(s) {
+			delete(that.streaks, key)
+		}
+	}
+	that.mu.Unlock()
+
+	for _, summary := range summaries {
+		that.exporter.Export(ctx, summary)
+	}
+}
+
+func (that *Exporter) expired(s *streak, now time.Time) bool {
+	return that.window > 0 && now.Sub(s.start) >= that.window
+}
+
+// summarize returns the summary entry of repeats and resets their number.
+func (that *Exporter) summarize(s *streak, now time.Time) *log.Entry {
+	if s.count == 0 {
+		return nil
+	}
+
+	data := s.entry.Fields().Clone()
+	data[FieldKeyRepeated] = s.count
+	data[FieldKeyRepeatedMessage] = s.entry.Message
+
+	summary := &log.Entry{
+		Logger:  s.entry.Logger,
+		Time:    now,
+		Level:   s.entry.Level,
+		Message: repeatedMessage(s.count),
+		Data:    data,
+	}
+	s.count = 0
+	return summary
+}
+
+func (that *Exporter) signature(entry *log.Entry) string {
+	var b strings.Builder
+	b.WriteString(entry.Level.String())
+	b.WriteByte(0)
+	b.WriteString(entry.Message)
+	data := entry.Data
+	if len(that.fields) != 0 {
+		data = entry.Fields()
+	}
+	for _, field := range that.fields {
+		b.WriteByte(0)
+		if v, ok := data[field]; ok {
+			fmt.Fprint(&b, v)
+		}
+	}
+	return b.String()
+}
+
+func newStreak(key string, entry *log.Entry, now time.Time) *streak {
+	return &streak{
+		key:   key,
+		entry: entry.Snapshot(),
+		start: now,
+	}
+}
+
+func repeatedMessage(count uint64) string {
+	if count == 1 {
+		return "last message repeated once"
+	}
+	return fmt.Sprintf("last message repeated %d times", count)
+}
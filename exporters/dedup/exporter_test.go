@@ -0,0 +1,120 @@
+package dedupExporter
+
+import (
+	"context"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type memoryExporter struct {
+	entries []*log.Entry
+}
+
+func (that *memoryExporter) Export(ctx context.Context, entry *log.Entry) {
+	that.entries = append(that.entries, entry.Snapshot())
+}
+
+func (that *memoryExporter) messages() []string {
+	var messages []string
+	for _, entry := range that.entries {
+		messages = append(messages, entry.Message)
+	}
+	that.entries = nil
+	return messages
+}
+
+func TestExporter(t *testing.T) {
+	type Test struct {
+		name     string
+		mode     Mode
+		expected []string
+	}
+
+	tests := []Test{
+		{
+			name: "consecutive",
+			mode: ModeConsecutive,
+			expected: []string{
+				"db down", "last message repeated 2 times", "db down",
+				"cache down", "db down", "last message repeated once",
+				"db down",
+			},
+		},
+		{
+			name: "window",
+			mode: ModeWindow,
+			expected: []string{
+				"db down", "db down", "cache down",
+				"last message repeated 4 times", "db down",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			target := &memoryExporter{}
+
+			exporter, err := NewBuilder().
+				WithExporter(target).
+				WithMode(test.mode).
+				WithFields("code").
+				WithWindow(time.Hour).
+				WithClock(func() time.Time { return now }).
+				Build()
+			require.NoError(t, err)
+
+			logger, err := log.NewBuilder().
+				WithExporter(exporter).
+				Build()
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			db := logger.WithField("code", 1)
+			db.Error(ctx, "db down")
+			db.Error(ctx, "db down")
+			db.Error(ctx, "db down")
+			logger.WithField("code", 2).Error(ctx, "db down")
+			logger.WithField("code", 3).Error(ctx, "cache down")
+			db.Error(ctx, "db down")
+			db.Error(ctx, "db down")
+
+			now = now.Add(time.Hour)
+			db.Error(ctx, "db down")
+
+			assert.Equal(t, test.expected, target.messages())
+
+			require.NoError(t, logger.Close(ctx))
+			assert.Empty(t, target.entries)
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	target := &memoryExporter{}
+
+	exporter, err := NewBuilder().
+		WithExporter(target).
+		Build()
+	require.NoError(t, err)
+
+	logger, err := log.NewBuilder().
+		WithExporter(exporter).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		logger.WithField("code", 1).Warning(ctx, "retry")
+	}
+	require.NoError(t, logger.Close(ctx))
+
+	require.Len(t, target.entries, 2)
+	summary := target.entries[1]
+	assert.Equal(t, log.WarnLevel, summary.Level)
+	assert.Equal(t, "last message repeated 4 times", summary.Message)
+	assert.Equal(t, log.Fields{"code": 1, FieldKeyRepeated: uint64(4), FieldKeyRepeatedMessage: "retry"}, summary.Data)
+}
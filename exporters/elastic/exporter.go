@@ -4,12 +4,15 @@ import (
 	"context"
 	"github.com/adverax/log"
 	"github.com/olivere/elastic/v7"
+	"sync"
 )
 
 type Exporter struct {
 	client    *elastic.Client
 	formatter log.Formatter
 	index     string
+	mu        sync.RWMutex
+	closed    bool
 }
 
 func New(
@@ -25,6 +28,13 @@ func New(
 }
 
 func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
+	that.mu.RLock()
+	defer that.mu.RUnlock()
+
+	if that.closed {
+		return
+	}
+
 	data, err := that.formatter.Format(entry)
 	if err != nil {
 		return
@@ -36,3 +46,13 @@ func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
 		Do(ctx)
 	// nothing
 }
+
+// Close waits for running exports and rejects further entries.
+// The client itself is owned by the caller and is not stopped.
+func (that *Exporter) Close(ctx context.Context) error {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	that.closed = true
+	return nil
+}
@@ -53,3 +53,39 @@ func (that *Exporter) export(entry *log.Entry) {
 		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
 	}
 }
+
+// Flush commits written data of the output to the storage.
+func (that *Exporter) Flush(ctx context.Context) error {
+	if that.isStandard() {
+		return nil
+	}
+
+	switch out := that.out.(type) {
+	case interface{ Sync() error }:
+		return out.Sync()
+	case interface{ Flush() error }:
+		return out.Flush()
+	default:
+		return nil
+	}
+}
+
+// Close flushes and closes the output. Standard streams are never closed.
+func (that *Exporter) Close(ctx context.Context) error {
+	if that.isStandard() {
+		return nil
+	}
+
+	if err := that.Flush(ctx); err != nil {
+		return err
+	}
+
+	if closer, ok := that.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (that *Exporter) isStandard() bool {
+	return that.out == os.Stdout || that.out == os.Stderr
+}
@@ -1,9 +1,12 @@
 package logFileRotator
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type Builder struct {
@@ -14,12 +17,17 @@ func NewBuilder() *Builder {
 	return &Builder{
 		engine: &Engine{
 			options: Options{
-				maxSize:    10000000,
-				maxAge:     30,
-				maxBackups: 30,
-				localTime:  false,
-				timeFormat: "2006-01-02T15-04-05.000",
+				maxSize:        10000000,
+				retention:      30 * 24 * time.Hour,
+				maxBackups:     30,
+				localTime:      false,
+				timeFormat:     "2006-01-02T15-04-05.000",
+				namingTemplate: DefaultNamingTemplate,
+				errorHandler:   reportError,
+				codec:          Gzip(gzip.BestCompression),
+				checkInterval:  time.Second,
 			},
+			clock: systemClock{},
 		},
 	}
 }
@@ -29,13 +37,29 @@ func (that *Builder) WithFileName(filename string) *Builder {
 	return that
 }
 
+// WithMaxSize sets the size of file, since that it is rotated. Zero disables rotation by size.
 func (that *Builder) WithMaxSize(maxSize int) *Builder {
 	that.engine.options.maxSize = maxSize
 	return that
 }
 
+// WithMaxAge sets the maximum age of backups in days. Zero disables removal by age.
 func (that *Builder) WithMaxAge(maxAge int) *Builder {
-	that.engine.options.maxAge = maxAge
+	return that.WithRetention(time.Duration(maxAge) * 24 * time.Hour)
+}
+
+// WithRetention sets the maximum age of backups. Zero disables removal by age.
+// The age is taken from the timestamp of backup or from the time of modification,
+// if the naming template has no timestamp.
+func (that *Builder) WithRetention(retention time.Duration) *Builder {
+	that.engine.options.retention = retention
+	return that
+}
+
+// WithMaxTotalSize sets the maximum total size of backups in bytes.
+// The oldest backups are removed to fit it. Zero disables the limit.
+func (that *Builder) WithMaxTotalSize(maxTotalSize int64) *Builder {
+	that.engine.options.maxTotalSize = maxTotalSize
 	return that
 }
 
@@ -49,14 +73,121 @@ func (that *Builder) WithLocalTime(localTime bool) *Builder {
 	return that
 }
 
+// WithTimeFormat sets the layout of timestamps in names of backups.
+func (that *Builder) WithTimeFormat(timeFormat string) *Builder {
+	that.engine.options.timeFormat = timeFormat
+	return that
+}
+
+// WithNamingTemplate sets the template of names of backups, e.g. "{prefix}{ext}.{seq}"
+// or "{prefix}-{hostname}-{timestamp}{ext}". The template must contain {timestamp} or {seq}.
+// Sequence numbers grow, so the newest backup has the greatest number.
+// The extension of codec is appended to names of compressed backups.
+func (that *Builder) WithNamingTemplate(template string) *Builder {
+	that.engine.options.namingTemplate = template
+	return that
+}
+
+// WithArchiveDir sets the directory of backups. Backups are kept next to the file by default.
+func (that *Builder) WithArchiveDir(dir string) *Builder {
+	that.engine.options.archiveDir = dir
+	return that
+}
+
+// WithErrorHandler sets the handler of errors of rotation in background, e.g. of cleanup.
+// Errors are printed to stderr by default.
+func (that *Builder) WithErrorHandler(handler func(err error)) *Builder {
+	that.engine.options.errorHandler = handler
+	return that
+}
+
+// WithSchedule enables rotation by time, e.g. Hourly() or DailyAt(0, 0).
+// It is combined with rotation by size.
+func (that *Builder) WithSchedule(schedule Schedule) *Builder {
+	that.engine.options.schedule = schedule
+	return that
+}
+
+// WithCodec sets the codec of backups, e.g. Gzip(gzip.DefaultCompression) or Zstd(3).
+// Nil disables compression. Backups are compressed by gzip with the best compression by default.
+func (that *Builder) WithCodec(codec Codec) *Builder {
+	that.engine.options.codec = codec
+	return that
+}
+
+// WithCheckInterval sets the interval of checks, that the file was replaced or truncated
+// by external tools, e.g. logrotate. The file is reopened in this case.
+// Zero checks the file before every write, negative disables checks. Default is one second.
+func (that *Builder) WithCheckInterval(interval time.Duration) *Builder {
+	that.engine.options.checkInterval = interval
+	return that
+}
+
+// WithReopenSignals sets signals, that make the engine to reopen the file, e.g. syscall.SIGHUP.
+func (that *Builder) WithReopenSignals(signals ...os.Signal) *Builder {
+	that.engine.options.reopenSignals = signals
+	return that
+}
+
+// WithSymlink enables the symlink to the file at path, e.g. "/var/log/app/current",
+// that is updated, when the file is opened.
+func (that *Builder) WithSymlink(path string) *Builder {
+	that.engine.options.symlink = path
+	return that
+}
+
+// WithOnRotate sets the callback, that is called after rotation with the path of sealed backup
+// and the path of the new file. It is called in background before the backup is compressed
+// or moved to the archive directory, so the backup stays in place until it returns.
+func (that *Builder) WithOnRotate(callback func(oldPath, newPath string)) *Builder {
+	that.engine.options.onRotate = callback
+	return that
+}
+
+// WithOnCompressed sets the callback, that is called in background with the path
+// of compressed backup.
+func (that *Builder) WithOnCompressed(callback func(path string)) *Builder {
+	that.engine.options.onCompressed = callback
+	return that
+}
+
+func (that *Builder) WithClock(clock Clock) *Builder {
+	that.engine.clock = clock
+	return that
+}
+
 func (that *Builder) Build() (*Engine, error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
 	if err := that.updateDefaultFields(); err != nil {
 		return nil, err
 	}
 
+	that.engine.start()
 	return that.engine, nil
 }
 
+func (that *Builder) checkRequiredFields() error {
+	if that.engine.clock == nil {
+		return ErrRequiredFieldClock
+	}
+	if schedule := that.engine.options.schedule; schedule != nil {
+		// The schedule must move forward, otherwise the file is rotated on every write.
+		now := that.engine.clock.Now()
+		if !schedule.Next(now).After(now) {
+			return ErrInvalidSchedule
+		}
+	}
+	if that.engine.options.errorHandler == nil {
+		return ErrRequiredFieldErrorHandler
+	}
+	if that.engine.options.timeFormat == "" {
+		return ErrRequiredFieldTimeFormat
+	}
+	return nil
+}
+
 func (that *Builder) updateDefaultFields() error {
 	if that.engine.options.fileName == "" {
 		var err error
@@ -66,6 +197,21 @@ func (that *Builder) updateDefaultFields() error {
 		}
 	}
 
+	location := time.UTC
+	if that.engine.options.localTime {
+		location = time.Local
+	}
+	naming, err := newNaming(
+		that.engine.options.namingTemplate,
+		that.engine.options.fileName,
+		that.engine.options.timeFormat,
+		location,
+	)
+	if err != nil {
+		return err
+	}
+	that.engine.naming = naming
+
 	return nil
 }
 
@@ -83,3 +229,15 @@ func (that *Builder) createDefaultFileName() (string, error) {
 	file := filepath.Base(os.Args[0]) + ".log"
 	return filepath.Join(dir, file), nil
 }
+
+func reportError(err error) {
+	fmt.Fprintf(os.Stderr, "Failed to rotate log file, %v\n", err)
+}
+
+var (
+	ErrRequiredFieldClock        = errors.New("clock is required")
+	ErrRequiredFieldErrorHandler = errors.New("error handler is required")
+	ErrRequiredFieldTimeFormat   = errors.New("time format is required")
+	ErrInvalidNamingTemplate     = errors.New("invalid naming template")
+	ErrInvalidSchedule           = errors.New("schedule must return moments after the given time")
+)
@@ -0,0 +1,55 @@
+package logFileRotator
+
+import (
+	"compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"io"
+)
+
+// Codec compresses rotated files.
+type Codec interface {
+	// Extension is appended to the name of compressed file, e.g. ".gz".
+	Extension() string
+	// NewWriter returns the writer, that compresses data into w. Closing it does not close w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+// compressedExtensions are extensions of all known codecs, so backups are recognized
+// after change of codec.
+var compressedExtensions = []string{".gz", ".zst"}
+
+const tmpExtension = ".tmp"
+
+type gzipCodec struct {
+	level int
+}
+
+// Gzip compresses files by gzip with level from gzip.HuffmanOnly to gzip.BestCompression.
+func Gzip(level int) Codec {
+	return gzipCodec{level: level}
+}
+
+func (that gzipCodec) Extension() string {
+	return ".gz"
+}
+
+func (that gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, that.level)
+}
+
+type zstdCodec struct {
+	level zstd.EncoderLevel
+}
+
+// Zstd compresses files by zstandard with level from 1 to 22 of the reference implementation.
+func Zstd(level int) Codec {
+	return zstdCodec{level: zstd.EncoderLevelFromZstd(level)}
+}
+
+func (that zstdCodec) Extension() string {
+	return ".zst"
+}
+
+func (that zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w, zstd.WithEncoderLevel(that.level))
+}
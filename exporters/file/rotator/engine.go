@@ -1,13 +1,13 @@
 package logFileRotator
 
 import (
-	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
-	"strings"
 	"sync"
 	"time"
 )
@@ -15,17 +15,149 @@ import (
 type Options struct {
 	fileName   string
 	maxSize    int
-	maxAge     int
 	maxBackups int
-	localTime  bool
-	timeFormat string
+	// retention is the maximum age of backups.
+	retention time.Duration
+	// maxTotalSize is the maximum total size of backups.
+	maxTotalSize   int64
+	localTime      bool
+	timeFormat     string
+	namingTemplate string
+	archiveDir     string
+	errorHandler   func(err error)
+	// symlink is the path of the symlink to the file.
+	symlink      string
+	onRotate     func(oldPath, newPath string)
+	onCompressed func(path string)
+	schedule     Schedule
+	codec        Codec
+	// checkInterval is the interval of checks for replacement and truncation of file.
+	checkInterval time.Duration
+	// reopenSignals are signals, that make the engine to reopen the file.
+	reopenSignals []os.Signal
 }
 
 type Engine struct {
 	options Options
-	size    int64
-	file    *os.File
-	mu      sync.Mutex
+	naming  *naming
+	clock   Clock
+	// seq is the sequence number of the last backup, zero until it is loaded.
+	seq  int
+	size int64
+	file *os.File
+	// nextRotation is the moment of the next rotation by schedule.
+	nextRotation time.Time
+	// checkedAt is the moment of the last check of file.
+	checkedAt time.Time
+	mu        sync.Mutex
+	stop      chan struct{}
+	stopOnce  sync.Once
+	// workers are the timer and the handler of signals.
+	workers sync.WaitGroup
+	// jobs are compressions and cleanups in background, jobMu serializes them.
+	jobs  sync.WaitGroup
+	jobMu sync.Mutex
+}
+
+// start starts the timer, that rotates the file by schedule, so idle files
+// are rotated in time too, and the handler of signals, that reopen the file.
+func (that *Engine) start() {
+	that.recoverBackups()
+	that.stop = make(chan struct{})
+
+	if that.options.schedule != nil {
+		that.workers.Add(1)
+		go that.run()
+	}
+
+	if len(that.options.reopenSignals) != 0 {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, that.options.reopenSignals...)
+		that.workers.Add(1)
+		go that.handleSignals(signals)
+	}
+}
+
+func (that *Engine) handleSignals(signals chan os.Signal) {
+	defer that.workers.Done()
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-that.stop:
+			return
+		case <-signals:
+			if err := that.Reopen(); err != nil {
+				that.error("Reopen by signal:", err)
+			}
+		}
+	}
+}
+
+func (that *Engine) run() {
+	defer that.workers.Done()
+
+	for {
+		now := that.now()
+		that.mu.Lock()
+		next := that.nextRotation
+		if next.IsZero() || that.file == nil && !next.After(now) {
+			next = that.options.schedule.Next(now)
+		}
+		that.mu.Unlock()
+
+		select {
+		case <-that.stop:
+			return
+		case <-that.clock.After(next.Sub(now)):
+			that.mu.Lock()
+			if that.file != nil && that.rotationDue(that.now()) {
+				if err := that.rotateOnSchedule(); err != nil {
+					that.error("Rotate by schedule:", err)
+				}
+			}
+			that.mu.Unlock()
+		}
+	}
+}
+
+// now returns the current time in the time zone of the engine.
+func (that *Engine) now() time.Time {
+	t := that.clock.Now()
+	if !that.options.localTime {
+		t = t.UTC()
+	}
+	return t
+}
+
+func (that *Engine) rotationDue(now time.Time) bool {
+	return that.options.schedule != nil && !that.nextRotation.IsZero() && !now.Before(that.nextRotation)
+}
+
+// rotateOnSchedule rotates the file, unless it is empty, so empty backups are not created.
+func (that *Engine) rotateOnSchedule() error {
+	if that.size == 0 {
+		that.scheduleRotation()
+		return nil
+	}
+	return that.rotate()
+}
+
+func (that *Engine) scheduleRotation() {
+	if that.options.schedule != nil {
+		that.nextRotation = that.options.schedule.Next(that.now())
+	}
+}
+
+// expired reports whether the file modified at modTime belongs to the past period of schedule.
+func (that *Engine) expired(modTime time.Time) bool {
+	if that.options.schedule == nil {
+		return false
+	}
+	if !that.options.localTime {
+		modTime = modTime.UTC()
+	}
+	return !that.now().Before(that.options.schedule.Next(modTime))
 }
 
 func (that *Engine) openExistingOrNew(writeLen int) error {
@@ -37,7 +169,10 @@ func (that *Engine) openExistingOrNew(writeLen int) error {
 		return fmt.Errorf("error getting log file info: %s", err)
 	}
 
-	if info.Size()+int64(writeLen) >= int64(that.options.maxSize) {
+	if that.options.maxSize > 0 && info.Size()+int64(writeLen) >= int64(that.options.maxSize) {
+		return that.rotate()
+	}
+	if info.Size() > 0 && that.expired(info.ModTime()) {
 		return that.rotate()
 	}
 
@@ -48,38 +183,308 @@ func (that *Engine) openExistingOrNew(writeLen int) error {
 
 	that.file = file
 	that.size = info.Size()
+	that.checkedAt = that.clock.Now()
+	that.scheduleRotation()
+	that.link()
 	return nil
 }
 
-func (that *Engine) compress(name string) {
-	inFile := name
-	outFile := name + ".gz"
-	zipHandle, err := os.OpenFile(outFile, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
+// Reopen closes the file and opens the file at the path again, e.g. after it is
+// moved by logrotate. The file is not rotated.
+func (that *Engine) Reopen() error {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	return that.reopen()
+}
+
+func (that *Engine) reopen() error {
+	if err := that.close(); err != nil {
+		that.error("Reopen: close:", err)
+	}
+
+	if err := os.MkdirAll(that.dir(), 0744); err != nil {
+		return fmt.Errorf("can't make directories for logfile: %s", err)
+	}
+
+	file, err := os.OpenFile(that.options.fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
-		that.error("Compress: Opening file:", err)
-		return
+		return fmt.Errorf("can't open logfile: %s", err)
 	}
-	defer zipHandle.Close()
 
-	zipWriter, err := gzip.NewWriterLevel(zipHandle, 9)
+	info, err := file.Stat()
 	if err != nil {
-		that.error("Compress: New gzip writer:", err)
+		_ = file.Close()
+		return fmt.Errorf("error getting log file info: %s", err)
+	}
+
+	that.file = file
+	that.size = info.Size()
+	that.checkedAt = that.clock.Now()
+	if that.nextRotation.IsZero() {
+		that.scheduleRotation()
+	}
+	that.link()
+	return nil
+}
+
+// link points the symlink to the file, so tools can tail the fixed path.
+// The symlink is replaced atomically.
+func (that *Engine) link() {
+	link := that.options.symlink
+	if link == "" {
 		return
 	}
-	defer zipWriter.Close()
 
-	inReader, err := os.OpenFile(inFile, os.O_RDONLY, 0666)
+	target, err := filepath.Abs(that.options.fileName)
 	if err != nil {
-		that.error("Compress: Opening old log file:", err)
+		that.error("Symlink:", err)
 		return
 	}
-	defer inReader.Close()
+	if absLink, err := filepath.Abs(link); err == nil {
+		if rel, err := filepath.Rel(filepath.Dir(absLink), target); err == nil {
+			target = rel
+		}
+	}
 
-	_, err = io.Copy(zipWriter, inReader)
-	if err != nil {
-		that.error("Compress: copy", err)
+	if current, err := os.Readlink(link); err == nil && current == target {
 		return
 	}
+
+	tmpLink := link + tmpExtension
+	_ = os.Remove(tmpLink)
+	if err := os.Symlink(target, tmpLink); err != nil {
+		that.error("Symlink:", err)
+		return
+	}
+	if err := os.Rename(tmpLink, link); err != nil {
+		_ = os.Remove(tmpLink)
+		that.error("Symlink:", err)
+	}
+}
+
+// check detects, that the file was replaced or truncated by external tools,
+// e.g. by logrotate with options create or copytruncate.
+func (that *Engine) check() error {
+	interval := that.options.checkInterval
+	if interval < 0 {
+		return nil
+	}
+
+	now := that.clock.Now()
+	if now.Sub(that.checkedAt) < interval {
+		return nil
+	}
+	that.checkedAt = now
+
+	pathInfo, err := os.Stat(that.options.fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return that.reopen()
+		}
+		return fmt.Errorf("error getting log file info: %s", err)
+	}
+
+	fileInfo, err := that.file.Stat()
+	if err != nil {
+		return that.reopen()
+	}
+
+	if !os.SameFile(pathInfo, fileInfo) {
+		return that.reopen()
+	}
+	if fileInfo.Size() < that.size {
+		that.size = fileInfo.Size()
+	}
+	return nil
+}
+
+// archive compresses the backup and removes old backups in background, so writes
+// are not blocked. Jobs are serialized, so cleanup waits for compression in flight.
+// Callbacks are called by the job too: OnRotate before the backup is archived,
+// and OnCompressed after it is compressed.
+func (that *Engine) archive(backup string, rotated bool) {
+	that.jobs.Add(1)
+	go func() {
+		defer that.jobs.Done()
+
+		that.jobMu.Lock()
+		defer that.jobMu.Unlock()
+
+		if rotated && that.options.onRotate != nil {
+			that.options.onRotate(backup, that.options.fileName)
+		}
+		if backup != "" {
+			target, err := that.store(backup)
+			if err != nil {
+				that.error("Archive:", err)
+			} else if that.options.codec != nil && that.options.onCompressed != nil {
+				that.options.onCompressed(target)
+			}
+		}
+		if err := that.cleanup(); err != nil {
+			that.error("Cleanup:", err)
+		}
+	}()
+}
+
+// store compresses the backup or moves it into the archive directory.
+// It returns the path of the archived backup.
+func (that *Engine) store(name string) (string, error) {
+	dir := that.archiveDir()
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return "", fmt.Errorf("can't make archive directory: %w", err)
+	}
+
+	if codec := that.options.codec; codec != nil {
+		target := that.archiveTarget(dir, name, codec.Extension())
+		return target, that.transfer(name, target, func(w io.Writer, r io.Reader) error {
+			return encode(codec, w, r)
+		})
+	}
+
+	target := that.archiveTarget(dir, name, "")
+	if target == name {
+		return target, nil
+	}
+	if err := os.Rename(name, target); err == nil {
+		return target, nil
+	}
+	// The archive directory may be on another device.
+	return target, that.transfer(name, target, func(w io.Writer, r io.Reader) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+}
+
+// archiveTarget returns the path of the archived backup with extension, that does not
+// overwrite existing file. Backups of previous processes may have the same name.
+func (that *Engine) archiveTarget(dir, name, ext string) string {
+	base := filepath.Base(name)
+	target := filepath.Join(dir, base) + ext
+	for dup := 1; target != name && fileExists(target); dup++ {
+		target = filepath.Join(dir, that.naming.duplicate(base, dup)) + ext
+	}
+	return target
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Lstat(filename)
+	return err == nil
+}
+
+// transfer writes the content of file into the temporary file, that is renamed to target
+// on success, so target is never half-written. The source file is removed only afterward.
+func (that *Engine) transfer(name, target string, copy func(w io.Writer, r io.Reader) error) error {
+	tmpFile := target + tmpExtension
+
+	in, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("can't open backup: %w", err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("can't get backup info: %w", err)
+	}
+
+	out, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return fmt.Errorf("can't create file: %w", err)
+	}
+
+	if err := copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("can't write %s: %w", target, err)
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("can't sync %s: %w", target, err)
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("can't close %s: %w", target, err)
+	}
+
+	if err := os.Rename(tmpFile, target); err != nil {
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("can't rename %s: %w", tmpFile, err)
+	}
+
+	that.removeFile(name)
+	return nil
+}
+
+func encode(codec Codec, out io.Writer, in io.Reader) error {
+	w, err := codec.NewWriter(out)
+	if err != nil {
+		return fmt.Errorf("can't create encoder: %w", err)
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// recoverBackups completes archiving interrupted by crash: half-written files are removed
+// and backups left in place are archived again. A backup, whose archived copy is complete,
+// is removed, because the crash happened after the archived copy was renamed.
+func (that *Engine) recoverBackups() {
+	dirs := []string{that.dir()}
+	if that.archiveDir() != that.dir() {
+		dirs = append(dirs, that.archiveDir())
+	}
+
+	var backups []string
+	temporary := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				that.error("Recover:", err)
+			}
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := that.naming.parse(entry.Name())
+			if entry.IsDir() || !ok {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			switch {
+			case name.temporary:
+				temporary[path] = true
+				that.removeFile(path)
+			case name.compressed:
+			case that.options.codec != nil || dir != that.archiveDir():
+				backups = append(backups, path)
+			}
+		}
+	}
+
+	for _, backup := range backups {
+		target := that.archivedPath(backup)
+		if target != backup && fileExists(target) && !temporary[target+tmpExtension] {
+			that.removeFile(backup)
+			continue
+		}
+		that.archive(backup, false)
+	}
+}
+
+// archivedPath returns the path of the archived backup without resolving collisions.
+func (that *Engine) archivedPath(name string) string {
+	target := filepath.Join(that.archiveDir(), filepath.Base(name))
+	if codec := that.options.codec; codec != nil {
+		target += codec.Extension()
+	}
+	return target
 }
 
 func (that *Engine) removeFile(filename string) {
@@ -101,25 +506,27 @@ func (that *Engine) openNew() error {
 	info, err := os.Stat(filename)
 	if err == nil {
 		mode = info.Mode()
-		newName := that.backupName(filename, that.options.localTime)
+		newName := filepath.Join(that.dir(), that.backupName())
 		if err := os.Rename(filename, newName); err != nil {
 			return fmt.Errorf("can't rename log file: %s", err)
 		}
 
-		that.compress(newName)
-		that.removeFile(newName)
+		that.archive(newName, true)
 		if err := chown(filename, info); err != nil {
 			return err
 		}
 	}
 
-	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, mode)
 	if err != nil {
 		return fmt.Errorf("can't open new logfile: %s", err)
 	}
 
 	that.file = f
 	that.size = 0
+	that.checkedAt = that.clock.Now()
+	that.scheduleRotation()
+	that.link()
 	return nil
 }
 
@@ -132,7 +539,7 @@ func (that *Engine) Write(p []byte) (n int, err error) {
 
 func (that *Engine) write(p []byte) (n int, err error) {
 	writeLen := len(p)
-	if writeLen > that.options.maxSize {
+	if that.options.maxSize > 0 && writeLen > that.options.maxSize {
 		return 0, fmt.Errorf(
 			"Record size(%d) exceeds max size of log(%d)", writeLen, that.options.maxSize,
 		)
@@ -142,9 +549,16 @@ func (that *Engine) write(p []byte) (n int, err error) {
 		if err = that.openExistingOrNew(len(p)); err != nil {
 			return 0, err
 		}
+	} else if err = that.check(); err != nil {
+		return 0, err
 	}
 
-	if that.size+int64(writeLen) > int64(that.options.maxSize) {
+	if that.rotationDue(that.now()) {
+		if err := that.rotateOnSchedule(); err != nil {
+			return 0, err
+		}
+	}
+	if that.options.maxSize > 0 && that.size+int64(writeLen) > int64(that.options.maxSize) {
 		if err := that.rotate(); err != nil {
 			return 0, err
 		}
@@ -171,17 +585,37 @@ func (that *Engine) rotate() error {
 		return err
 	}
 
-	if err := that.openNew(); err != nil {
-		return err
-	}
-	return that.cleanup()
+	return that.openNew()
 }
 
+// Close closes the file, stops rotation by timer and handling of signals, and waits
+// for compression of backups.
+// The file is reopened by the next write, and then it is rotated by schedule lazily.
 func (that *Engine) Close() error {
+	that.stopOnce.Do(func() {
+		if that.stop != nil {
+			close(that.stop)
+			that.workers.Wait()
+		}
+	})
+
+	that.mu.Lock()
+	err := that.close()
+	that.mu.Unlock()
+
+	that.jobs.Wait()
+	return err
+}
+
+func (that *Engine) Sync() error {
 	that.mu.Lock()
 	defer that.mu.Unlock()
 
-	return that.close()
+	if that.file == nil {
+		return nil
+	}
+
+	return that.file.Sync()
 }
 
 func (that *Engine) close() error {
@@ -194,8 +628,19 @@ func (that *Engine) close() error {
 	return err
 }
 
+// archiveDir returns the directory of backups.
+func (that *Engine) archiveDir() string {
+	if that.options.archiveDir != "" {
+		return that.options.archiveDir
+	}
+	return that.dir()
+}
+
+// cleanup removes backups over the limit of count or total size and backups older than retention.
+// Newer backups are kept first.
 func (that *Engine) cleanup() error {
-	if that.options.maxBackups == 0 && that.options.maxAge == 0 {
+	options := that.options
+	if options.maxBackups == 0 && options.retention == 0 && options.maxTotalSize == 0 {
 		return nil
 	}
 
@@ -205,124 +650,162 @@ func (that *Engine) cleanup() error {
 	}
 
 	var deletes []logInfo
-
-	if that.options.maxBackups > 0 && that.options.maxBackups < len(files) {
-		deletes = files[that.options.maxBackups:]
-		files = files[:that.options.maxBackups]
-	}
-	if that.options.maxAge > 0 {
-		diff := time.Duration(int64(24*time.Hour) * int64(that.options.maxAge))
-
-		cutoff := time.Now().Add(-1 * diff)
-
-		for _, f := range files {
-			if f.timestamp.Before(cutoff) {
-				deletes = append(deletes, f)
-			}
+	var kept int
+	var total int64
+	var full bool
+	cutoff := that.now().Add(-options.retention)
+	for _, f := range files {
+		if options.maxTotalSize > 0 && !full && total+f.size > options.maxTotalSize {
+			full = true
 		}
-	}
 
-	if len(deletes) == 0 {
-		return nil
+		switch {
+		case options.maxBackups > 0 && kept >= options.maxBackups,
+			options.retention > 0 && f.timestamp.Before(cutoff),
+			full:
+			deletes = append(deletes, f)
+		default:
+			kept++
+			total += f.size
+		}
 	}
 
-	go deleteAll(that.dir(), deletes)
-
-	return nil
+	return deleteAll(that.archiveDir(), deletes)
 }
 
-func deleteAll(dir string, files []logInfo) {
+func deleteAll(dir string, files []logInfo) error {
+	var errs []error
 	for _, f := range files {
-		_ = os.Remove(filepath.Join(dir, f.Name()))
-	}
-}
-
-func (that *Engine) prefixAndSuffix() (prefix, suffix string) {
-	filename := filepath.Base(that.options.fileName)
-	suffix = filepath.Ext(filename)
-	prefix = filename[:len(filename)-len(suffix)] + "-"
-	return prefix, suffix
-}
-
-func (that *Engine) timeFromName(filename, prefix, ext string) string {
-	if !strings.HasPrefix(filename, prefix) {
-		return ""
-	}
-
-	filename = filename[len(prefix):]
-	if strings.HasSuffix(filename, ext+".gz") {
-		ext = ext + ".gz"
-	}
-
-	if !strings.HasSuffix(filename, ext) {
-		return ""
+		if err := os.Remove(filepath.Join(dir, f.name)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
 	}
-
-	filename = filename[:len(filename)-len(ext)]
-	return filename
+	return errors.Join(errs...)
 }
 
+// oldLogFiles returns backups in the archive directory from newer to older.
 func (that *Engine) oldLogFiles() ([]logInfo, error) {
-	entries, err := os.ReadDir(that.dir())
+	entries, err := os.ReadDir(that.archiveDir())
 	if err != nil {
 		return nil, fmt.Errorf("can't read log file directory: %s", err)
 	}
-	var logFiles byFormatTime
 
-	prefix, ext := that.prefixAndSuffix()
+	var logFiles []logInfo
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
 
-		name := that.timeFromName(entry.Name(), prefix, ext)
-		if name == "" {
+		name, ok := that.naming.parse(entry.Name())
+		if !ok || name.temporary {
 			continue
 		}
 
-		t, err := time.Parse(that.options.timeFormat, name)
-		if err == nil {
-			logFiles = append(logFiles, logInfo{timestamp: t, DirEntry: entry})
+		info, err := entry.Info()
+		if err != nil {
+			// The backup is removed meanwhile.
+			continue
 		}
+
+		timestamp := name.timestamp
+		if timestamp.IsZero() {
+			timestamp = info.ModTime()
+		}
+		logFiles = append(logFiles, logInfo{
+			name:      entry.Name(),
+			timestamp: timestamp,
+			seq:       name.seq,
+			dup:       name.dup,
+			size:      info.Size(),
+		})
 	}
 
-	sort.Sort(logFiles)
+	sort.Slice(logFiles, func(i, j int) bool {
+		a, b := logFiles[i], logFiles[j]
+		switch {
+		case that.naming.hasSeq() && a.seq != b.seq:
+			return a.seq > b.seq
+		case !that.naming.hasSeq() && !a.timestamp.Equal(b.timestamp):
+			return a.timestamp.After(b.timestamp)
+		}
+		// Duplicates are made by later rotations.
+		return a.dup > b.dup
+	})
 
 	return logFiles, nil
 }
 
 func (that *Engine) error(msg string, err error) {
-	fmt.Println(msg, err.Error())
+	that.options.errorHandler(fmt.Errorf("%s %w", msg, err))
 }
 
-func (that *Engine) backupName(name string, local bool) string {
-	dir := filepath.Dir(name)
-	filename := filepath.Base(name)
-	ext := filepath.Ext(filename)
-	prefix := filename[:len(filename)-len(ext)]
-	t := time.Now()
-	if !local {
-		t = t.UTC()
+// backupName returns the name of the next backup without directory.
+// The name is not taken by other backups, including archived ones:
+// the sequence number is bumped or the suffix of duplicate is added.
+func (that *Engine) backupName() string {
+	seq := 0
+	if that.naming.hasSeq() {
+		if that.seq == 0 {
+			that.seq = that.lastSeq()
+		}
+		that.seq++
+		seq = that.seq
 	}
-	timestamp := t.Format(that.options.timeFormat)
-	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, timestamp, ext))
-}
 
-type logInfo struct {
-	timestamp time.Time
-	os.DirEntry
+	now := that.now()
+	base := that.naming.format(now, seq)
+	name := base
+	for dup := 1; that.backupExists(name); dup++ {
+		if that.naming.hasSeq() {
+			that.seq++
+			name = that.naming.format(now, that.seq)
+		} else {
+			name = that.naming.duplicate(base, dup)
+		}
+	}
+	return name
 }
 
-type byFormatTime []logInfo
-
-func (b byFormatTime) Less(i, j int) bool {
-	return b[i].timestamp.After(b[j].timestamp)
+// backupExists reports whether the backup with the name exists in the directory of log file
+// or in the archive directory, compressed or not.
+func (that *Engine) backupExists(name string) bool {
+	if fileExists(filepath.Join(that.dir(), name)) {
+		return true
+	}
+	archived := filepath.Join(that.archiveDir(), name)
+	if fileExists(archived) {
+		return true
+	}
+	for _, ext := range compressedExtensions {
+		if fileExists(archived + ext) {
+			return true
+		}
+	}
+	return false
 }
 
-func (b byFormatTime) Swap(i, j int) {
-	b[i], b[j] = b[j], b[i]
+// lastSeq returns the maximum sequence number of backups, including backups,
+// that are not archived yet.
+func (that *Engine) lastSeq() int {
+	var seq int
+	for _, dir := range []string{that.dir(), that.archiveDir()} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if name, ok := that.naming.parse(entry.Name()); ok && name.seq > seq {
+				seq = name.seq
+			}
+		}
+	}
+	return seq
 }
 
-func (b byFormatTime) Len() int {
-	return len(b)
+type logInfo struct {
+	name      string
+	timestamp time.Time
+	seq       int
+	dup       int
+	size      int64
 }
@@ -0,0 +1,711 @@
+package logFileRotator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// fakeClock fires timers only when the time is advanced by tests.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+func (that *fakeClock) Now() time.Time {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	return that.now
+}
+
+func (that *fakeClock) After(d time.Duration) <-chan time.Time {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	at := that.now.Add(d)
+	if !at.After(that.now) {
+		ch <- that.now
+		return ch
+	}
+	that.timers = append(that.timers, fakeTimer{at: at, ch: ch})
+	return ch
+}
+
+func (that *fakeClock) Advance(d time.Duration) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	that.now = that.now.Add(d)
+	timers := that.timers[:0]
+	for _, timer := range that.timers {
+		if timer.at.After(that.now) {
+			timers = append(timers, timer)
+			continue
+		}
+		timer.ch <- that.now
+	}
+	that.timers = timers
+}
+
+func (that *fakeClock) Waiting() int {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	return len(that.timers)
+}
+
+func newTestEngine(t *testing.T, clock *fakeClock, schedule Schedule, maxSize int) (*Engine, string) {
+	return newTestEngineIn(t, t.TempDir(), clock, schedule, maxSize, Gzip(gzip.BestCompression))
+}
+
+func newTestEngineIn(t *testing.T, dir string, clock *fakeClock, schedule Schedule, maxSize int, codec Codec) (*Engine, string) {
+	engine, err := NewBuilder().
+		WithFileName(filepath.Join(dir, "app.log")).
+		WithMaxSize(maxSize).
+		WithMaxAge(0).
+		WithMaxBackups(0).
+		WithSchedule(schedule).
+		WithClock(clock).
+		WithCodec(codec).
+		Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = engine.Close() })
+	return engine, dir
+}
+
+func backups(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() != "app.log" {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSchedule(t *testing.T) {
+	type Test struct {
+		schedule Schedule
+		time     time.Time
+		next     time.Time
+	}
+
+	at := func(day, hour, minute, second int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, second, 0, time.UTC)
+	}
+
+	tests := map[string]Test{
+		"Hourly": {
+			schedule: Hourly(),
+			time:     at(1, 10, 30, 0),
+			next:     at(1, 11, 0, 0),
+		},
+		"Hourly at the beginning of hour": {
+			schedule: Hourly(),
+			time:     at(1, 10, 0, 0),
+			next:     at(1, 11, 0, 0),
+		},
+		"Daily later today": {
+			schedule: DailyAt(3, 15),
+			time:     at(1, 2, 0, 0),
+			next:     at(1, 3, 15, 0),
+		},
+		"Daily tomorrow": {
+			schedule: DailyAt(3, 15),
+			time:     at(1, 3, 15, 0),
+			next:     at(2, 3, 15, 0),
+		},
+		"Every": {
+			schedule: Every(15 * time.Minute),
+			time:     at(1, 10, 31, 10),
+			next:     at(1, 10, 45, 0),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.next, test.schedule.Next(test.time))
+		})
+	}
+}
+
+func TestEngineRotatesOnWriteBySchedule(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 23, 59, 0, 0, time.UTC)}
+	engine, dir := newTestEngine(t, clock, DailyAt(0, 0), 0)
+
+	_, err := engine.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = engine.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Empty(t, backups(t, dir))
+
+	// The timer is not fired, so the file is rotated lazily.
+	clock.mu.Lock()
+	clock.now = clock.now.Add(2 * time.Minute)
+	clock.mu.Unlock()
+
+	_, err = engine.Write([]byte("third\n"))
+	require.NoError(t, err)
+	engine.jobs.Wait()
+	assert.Equal(t, []string{"app-2024-03-02T00-01-00.000.log.gz"}, backups(t, dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(content))
+}
+
+func TestEngineRotatesIdleFileByTimer(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)}
+	engine, dir := newTestEngine(t, clock, Hourly(), 0)
+
+	_, err := engine.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return clock.Waiting() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(30 * time.Minute)
+
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"app-2024-03-01T11-00-00.000.log.gz"}, backups(t, dir))
+	}, time.Second, time.Millisecond)
+
+	// Empty file is not rotated at the next hour.
+	require.Eventually(t, func() bool { return clock.Waiting() == 1 }, time.Second, time.Millisecond)
+	clock.Advance(time.Hour)
+	require.Eventually(t, func() bool { return clock.Waiting() == 1 }, time.Second, time.Millisecond)
+	assert.Len(t, backups(t, dir), 1)
+}
+
+func TestEngineRotatesBySizeAndSchedule(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)}
+	engine, dir := newTestEngine(t, clock, Hourly(), 10)
+
+	_, err := engine.Write([]byte("12345678\n"))
+	require.NoError(t, err)
+	clock.mu.Lock()
+	clock.now = clock.now.Add(time.Second)
+	clock.mu.Unlock()
+	_, err = engine.Write([]byte("12345678\n"))
+	require.NoError(t, err)
+	engine.jobs.Wait()
+	assert.Equal(t, []string{"app-2024-03-01T10-30-01.000.log.gz"}, backups(t, dir))
+
+	clock.mu.Lock()
+	clock.now = clock.now.Add(time.Hour)
+	clock.mu.Unlock()
+	_, err = engine.Write([]byte("1\n"))
+	require.NoError(t, err)
+	engine.jobs.Wait()
+	assert.Equal(
+		t,
+		[]string{"app-2024-03-01T10-30-01.000.log.gz", "app-2024-03-01T11-30-01.000.log.gz"},
+		backups(t, dir),
+	)
+}
+
+func TestEngineRotatesExpiredFileOnOpen(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 2, 10, 0, 0, 0, time.UTC)}
+	engine, dir := newTestEngine(t, clock, DailyAt(0, 0), 0)
+
+	name := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(name, []byte("old\n"), 0644))
+	modTime := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(name, modTime, modTime))
+
+	_, err := engine.Write([]byte("new\n"))
+	require.NoError(t, err)
+	engine.jobs.Wait()
+	assert.Equal(t, []string{"app-2024-03-02T10-00-00.000.log.gz"}, backups(t, dir))
+}
+
+func TestBuilderRequiresClock(t *testing.T) {
+	_, err := NewBuilder().WithClock(nil).Build()
+	assert.ErrorIs(t, err, ErrRequiredFieldClock)
+}
+
+func TestBuilderRejectsInvalidSchedule(t *testing.T) {
+	schedules := map[string]Schedule{
+		"Zero interval":     Every(0),
+		"Negative interval": Every(-time.Minute),
+		"Past":              ScheduleFunc(func(t time.Time) time.Time { return t.Add(-time.Second) }),
+	}
+
+	for name, schedule := range schedules {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewBuilder().
+				WithFileName(filepath.Join(t.TempDir(), "app.log")).
+				WithSchedule(schedule).
+				Build()
+			assert.ErrorIs(t, err, ErrInvalidSchedule)
+		})
+	}
+}
+
+func decompress(t *testing.T, name string) string {
+	file, err := os.Open(name)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var r io.Reader
+	switch filepath.Ext(name) {
+	case ".gz":
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		r = gz
+	case ".zst":
+		zr, err := zstd.NewReader(file)
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	default:
+		r = file
+	}
+
+	var b bytes.Buffer
+	_, err = io.Copy(&b, r)
+	require.NoError(t, err)
+	return b.String()
+}
+
+func TestEngineCodecs(t *testing.T) {
+	type Test struct {
+		codec  Codec
+		backup string
+	}
+
+	tests := map[string]Test{
+		"None": {
+			backup: "app-2024-03-01T10-00-00.000.log",
+		},
+		"Gzip": {
+			codec:  Gzip(gzip.BestSpeed),
+			backup: "app-2024-03-01T10-00-00.000.log.gz",
+		},
+		"Zstd": {
+			codec:  Zstd(3),
+			backup: "app-2024-03-01T10-00-00.000.log.zst",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+			engine, dir := newTestEngineIn(t, t.TempDir(), clock, nil, 0, test.codec)
+
+			_, err := engine.Write([]byte("first\n"))
+			require.NoError(t, err)
+			require.NoError(t, engine.Rotate())
+			require.NoError(t, engine.Close())
+
+			require.Equal(t, []string{test.backup}, backups(t, dir))
+			assert.Equal(t, "first\n", decompress(t, filepath.Join(dir, test.backup)))
+		})
+	}
+}
+
+func TestEngineRecoversInterruptedCompression(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	// Compression of the first backup is interrupted, the second one is not started.
+	write("app-2024-03-01T10-00-00.000.log", "first\n")
+	write("app-2024-03-01T10-00-00.000.log.gz.tmp", "garbage")
+	write("app-2024-03-01T11-00-00.000.log", "second\n")
+
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	engine, _ := newTestEngineIn(t, dir, clock, nil, 0, Gzip(gzip.BestSpeed))
+	require.NoError(t, engine.Close())
+
+	require.Equal(
+		t,
+		[]string{"app-2024-03-01T10-00-00.000.log.gz", "app-2024-03-01T11-00-00.000.log.gz"},
+		backups(t, dir),
+	)
+	assert.Equal(t, "first\n", decompress(t, filepath.Join(dir, "app-2024-03-01T10-00-00.000.log.gz")))
+	assert.Equal(t, "second\n", decompress(t, filepath.Join(dir, "app-2024-03-01T11-00-00.000.log.gz")))
+}
+
+func TestEngineRecoversCompressionInterruptedBeforeRemoval(t *testing.T) {
+	dir := t.TempDir()
+	// The crash happened after the compressed backup was renamed, but before the source was removed.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-2024-03-01T10-00-00.000.log"), []byte("first\n"), 0644))
+	var compressed bytes.Buffer
+	require.NoError(t, encode(Gzip(gzip.BestSpeed), &compressed, strings.NewReader("first\n")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-2024-03-01T10-00-00.000.log.gz"), compressed.Bytes(), 0644))
+
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	engine, _ := newTestEngineIn(t, dir, clock, nil, 0, Gzip(gzip.BestSpeed))
+	require.NoError(t, engine.Close())
+
+	require.Equal(t, []string{"app-2024-03-01T10-00-00.000.log.gz"}, backups(t, dir))
+	assert.Equal(t, "first\n", decompress(t, filepath.Join(dir, "app-2024-03-01T10-00-00.000.log.gz")))
+}
+
+func TestEngineCleanupWaitsForCompression(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	engine, err := NewBuilder().
+		WithFileName(filepath.Join(t.TempDir(), "app.log")).
+		WithMaxSize(0).
+		WithMaxAge(0).
+		WithMaxBackups(2).
+		WithClock(clock).
+		Build()
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, err := engine.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, engine.Rotate())
+		clock.Advance(time.Minute)
+	}
+	require.NoError(t, engine.Close())
+
+	assert.Equal(
+		t,
+		[]string{"app-2024-03-01T10-03-00.000.log.gz", "app-2024-03-01T10-04-00.000.log.gz"},
+		backups(t, filepath.Dir(engine.options.fileName)),
+	)
+}
+
+func readFile(t *testing.T, name string) string {
+	content, err := os.ReadFile(name)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestEngineDetectsExternalRotation(t *testing.T) {
+	type Test struct {
+		rotate   func(t *testing.T, name string)
+		current  string
+		external map[string]string
+	}
+
+	tests := map[string]Test{
+		"Rename and recreate": {
+			rotate: func(t *testing.T, name string) {
+				require.NoError(t, os.Rename(name, name+".1"))
+				require.NoError(t, os.WriteFile(name, nil, 0644))
+			},
+			current:  "second\n",
+			external: map[string]string{"app.log.1": "first\n"},
+		},
+		"Rename": {
+			rotate: func(t *testing.T, name string) {
+				require.NoError(t, os.Rename(name, name+".1"))
+			},
+			current:  "second\n",
+			external: map[string]string{"app.log.1": "first\n"},
+		},
+		"Copy and truncate": {
+			rotate: func(t *testing.T, name string) {
+				content, err := os.ReadFile(name)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(name+".1", content, 0644))
+				require.NoError(t, os.Truncate(name, 0))
+			},
+			current:  "second\n",
+			external: map[string]string{"app.log.1": "first\n"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+			engine, dir := newTestEngine(t, clock, nil, 100)
+			fileName := filepath.Join(dir, "app.log")
+
+			_, err := engine.Write([]byte("first\n"))
+			require.NoError(t, err)
+			test.rotate(t, fileName)
+
+			clock.Advance(time.Second)
+			_, err = engine.Write([]byte("second\n"))
+			require.NoError(t, err)
+			require.NoError(t, engine.Close())
+
+			assert.Equal(t, test.current, readFile(t, fileName))
+			assert.Equal(t, int64(len(test.current)), engine.size)
+			for external, content := range test.external {
+				assert.Equal(t, content, readFile(t, filepath.Join(dir, external)))
+			}
+		})
+	}
+}
+
+func TestEngineChecksFileByInterval(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	engine, dir := newTestEngine(t, clock, nil, 0)
+	fileName := filepath.Join(dir, "app.log")
+
+	_, err := engine.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(fileName, fileName+".1"))
+
+	// The file is not checked until the interval elapses.
+	_, err = engine.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", readFile(t, fileName+".1"))
+	assert.NoFileExists(t, fileName)
+}
+
+func TestEngineReopen(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	engine, dir := newTestEngine(t, clock, nil, 0)
+	fileName := filepath.Join(dir, "app.log")
+
+	_, err := engine.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(fileName, fileName+".1"))
+	require.NoError(t, engine.Reopen())
+
+	_, err = engine.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", readFile(t, fileName))
+	assert.Equal(t, "first\n", readFile(t, fileName+".1"))
+	assert.Equal(t, []string{"app.log.1"}, backups(t, dir))
+}
+
+func TestEngineArchivesNumberedBackups(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	// The backup of the previous process is numbered by the sequence too.
+	require.NoError(t, os.MkdirAll(archiveDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(archiveDir, "app.log.4.gz"), nil, 0644))
+
+	engine, err := NewBuilder().
+		WithFileName(filepath.Join(dir, "app.log")).
+		WithNamingTemplate("{prefix}{ext}.{seq}").
+		WithArchiveDir(archiveDir).
+		WithMaxBackups(2).
+		WithClock(clock).
+		Build()
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := engine.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, engine.Rotate())
+	}
+	require.NoError(t, engine.Close())
+
+	assert.Equal(t, []string{"archive"}, backups(t, dir))
+	assert.Equal(t, []string{"app.log.6.gz", "app.log.7.gz"}, backups(t, archiveDir))
+}
+
+func TestEngineCleanupLimits(t *testing.T) {
+	type Test struct {
+		configure func(builder *Builder) *Builder
+		backups   []string
+	}
+
+	tests := map[string]Test{
+		"Total size": {
+			configure: func(builder *Builder) *Builder {
+				return builder.WithMaxTotalSize(25)
+			},
+			backups: []string{"app-2024-03-01T13-00-00.000.log", "app-2024-03-01T14-00-00.000.log"},
+		},
+		"Retention": {
+			configure: func(builder *Builder) *Builder {
+				return builder.WithRetention(90 * time.Minute)
+			},
+			backups: []string{"app-2024-03-01T13-00-00.000.log", "app-2024-03-01T14-00-00.000.log"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+			engine, err := test.configure(NewBuilder()).
+				WithFileName(filepath.Join(dir, "app.log")).
+				WithMaxBackups(0).
+				WithCodec(nil).
+				WithClock(clock).
+				Build()
+			require.NoError(t, err)
+
+			for i := 0; i < 4; i++ {
+				clock.Advance(time.Hour)
+				_, err := engine.Write([]byte("0123456789\n"))
+				require.NoError(t, err)
+				require.NoError(t, engine.Rotate())
+			}
+			require.NoError(t, engine.Close())
+
+			assert.Equal(t, test.backups, backups(t, dir))
+		})
+	}
+}
+
+func TestEngineRetentionInLocalTime(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.Local)}
+	engine, err := NewBuilder().
+		WithFileName(filepath.Join(dir, "app.log")).
+		WithLocalTime(true).
+		WithRetention(90 * time.Minute).
+		WithMaxBackups(0).
+		WithCodec(nil).
+		WithClock(clock).
+		Build()
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		clock.Advance(time.Hour)
+		_, err := engine.Write([]byte("0123456789\n"))
+		require.NoError(t, err)
+		require.NoError(t, engine.Rotate())
+	}
+	require.NoError(t, engine.Close())
+
+	assert.Equal(t, []string{"app-2024-03-01T13-00-00.000.log", "app-2024-03-01T14-00-00.000.log"}, backups(t, dir))
+}
+
+func TestEngineReportsCleanupErrors(t *testing.T) {
+	dir := t.TempDir()
+	// The archive directory can not be created over the file.
+	archiveDir := filepath.Join(dir, "archive")
+	require.NoError(t, os.WriteFile(archiveDir, nil, 0644))
+
+	var mu sync.Mutex
+	var errs []error
+	engine, err := NewBuilder().
+		WithFileName(filepath.Join(dir, "app.log")).
+		WithArchiveDir(archiveDir).
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	_, err = engine.Write([]byte("line\n"))
+	require.NoError(t, err)
+	require.NoError(t, engine.Rotate())
+	require.NoError(t, engine.Close())
+
+	require.Len(t, errs, 3)
+	assert.ErrorContains(t, errs[0], "Recover:")
+	assert.ErrorContains(t, errs[1], "Archive:")
+	assert.ErrorContains(t, errs[2], "Cleanup:")
+}
+
+func TestEngineCallbacks(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "app.log")
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+
+	var events []string
+	engine, err := NewBuilder().
+		WithFileName(fileName).
+		WithClock(clock).
+		WithOnRotate(func(oldPath, newPath string) {
+			assert.Equal(t, "first\n", readFile(t, oldPath))
+			events = append(events, "rotate "+filepath.Base(oldPath)+" "+filepath.Base(newPath))
+		}).
+		WithOnCompressed(func(path string) {
+			assert.Equal(t, "first\n", decompress(t, path))
+			events = append(events, "compressed "+filepath.Base(path))
+		}).
+		Build()
+	require.NoError(t, err)
+
+	_, err = engine.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, engine.Rotate())
+	require.NoError(t, engine.Close())
+
+	assert.Equal(t, []string{
+		"rotate app-2024-03-01T10-00-00.000.log app.log",
+		"compressed app-2024-03-01T10-00-00.000.log.gz",
+	}, events)
+}
+
+func TestEngineKeepsBackupsWithSameName(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)}
+	// The backup of the previous process has the same name.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-2024-03-01.log"), []byte("previous\n"), 0644))
+
+	var mu sync.Mutex
+	var errs []error
+	engine, err := NewBuilder().
+		WithFileName(filepath.Join(dir, "app.log")).
+		WithTimeFormat("2006-01-02").
+		WithMaxSize(10).
+		WithMaxBackups(0).
+		WithClock(clock).
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	for _, line := range []string{"line one\n", "line two\n", "line 333\n", "line 444\n"} {
+		_, err := engine.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, engine.Close())
+
+	assert.Empty(t, errs)
+	assert.Equal(t, "line 444\n", readFile(t, filepath.Join(dir, "app.log")))
+	assert.Equal(t, []string{
+		"app-2024-03-01.log.1.gz",
+		"app-2024-03-01.log.2.gz",
+		"app-2024-03-01.log.3.gz",
+		"app-2024-03-01.log.gz",
+	}, backups(t, dir))
+
+	contents := map[string]bool{}
+	for _, name := range backups(t, dir) {
+		contents[decompress(t, filepath.Join(dir, name))] = true
+	}
+	assert.Equal(t, map[string]bool{
+		"previous\n": true,
+		"line one\n": true,
+		"line two\n": true,
+		"line 333\n": true,
+	}, contents)
+
+	files, err := engine.oldLogFiles()
+	require.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.name)
+	}
+	assert.Equal(t, []string{
+		"app-2024-03-01.log.3.gz",
+		"app-2024-03-01.log.2.gz",
+		"app-2024-03-01.log.1.gz",
+		"app-2024-03-01.log.gz",
+	}, names)
+}
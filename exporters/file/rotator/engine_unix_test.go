@@ -0,0 +1,69 @@
+//go:build unix
+
+package logFileRotator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestEngineReopensBySignal(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "app.log")
+	engine, err := NewBuilder().
+		WithFileName(fileName).
+		WithCheckInterval(-1).
+		WithReopenSignals(syscall.SIGHUP).
+		Build()
+	require.NoError(t, err)
+	defer engine.Close()
+
+	_, err = engine.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(fileName, fileName+".1"))
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(fileName)
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	_, err = engine.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", readFile(t, fileName))
+	assert.Equal(t, "first\n", readFile(t, fileName+".1"))
+}
+
+func TestEngineSymlink(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "current")
+	engine, err := NewBuilder().
+		WithFileName(filepath.Join(dir, "logs", "app.log")).
+		WithSymlink(link).
+		Build()
+	require.NoError(t, err)
+	defer engine.Close()
+
+	_, err = engine.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	target, err := os.Readlink(link)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("logs", "app.log"), target)
+
+	require.NoError(t, engine.Rotate())
+	_, err = engine.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", readFile(t, link))
+
+	// The symlink is restored, when the file is reopened.
+	require.NoError(t, os.Remove(link))
+	require.NoError(t, engine.Reopen())
+	assert.Equal(t, "second\n", readFile(t, link))
+}
@@ -0,0 +1,206 @@
+package logFileRotator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Placeholders of the naming template of backups.
+const (
+	// PlaceholderPrefix is the name of the log file without extension.
+	PlaceholderPrefix = "{prefix}"
+	// PlaceholderExt is the extension of the log file, e.g. ".log".
+	PlaceholderExt = "{ext}"
+	// PlaceholderTimestamp is the time of rotation in the time format of the engine.
+	PlaceholderTimestamp = "{timestamp}"
+	// PlaceholderSeq is the sequence number of the backup, that starts from 1.
+	PlaceholderSeq = "{seq}"
+	// PlaceholderHostname is the name of the host.
+	PlaceholderHostname = "{hostname}"
+	// PlaceholderPID is the identifier of the process.
+	PlaceholderPID = "{pid}"
+)
+
+// DefaultNamingTemplate names backups as "app-2006-01-02T15-04-05.000.log".
+const DefaultNamingTemplate = PlaceholderPrefix + "-" + PlaceholderTimestamp + PlaceholderExt
+
+var placeholderPattern = regexp.MustCompile(`\{[a-z]+\}`)
+
+// duplicatePattern recognizes the suffix, that is added to the name of backup on collision.
+var duplicatePattern = regexp.MustCompile(`^(.+)\.(\d+)$`)
+
+// naming formats names of backups by the template and recognizes them,
+// so backups are found for cleanup.
+type naming struct {
+	template   string
+	timeFormat string
+	location   *time.Location
+	prefix     string
+	ext        string
+	hostname   string
+	pattern    *regexp.Regexp
+	// timeIndex and seqIndex are indexes of submatches of the pattern, -1 if absent.
+	timeIndex int
+	seqIndex  int
+}
+
+// newNaming creates the naming. Timestamps of backups are parsed in the location,
+// that must match the time zone of formatted timestamps.
+func newNaming(template, fileName, timeFormat string, location *time.Location) (*naming, error) {
+	base := filepath.Base(fileName)
+	ext := filepath.Ext(base)
+	n := &naming{
+		template:   template,
+		timeFormat: timeFormat,
+		location:   location,
+		prefix:     base[:len(base)-len(ext)],
+		ext:        ext,
+		timeIndex:  -1,
+		seqIndex:   -1,
+	}
+
+	if strings.Contains(template, PlaceholderHostname) {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("can't get hostname: %w", err)
+		}
+		n.hostname = hostname
+	}
+
+	var pattern strings.Builder
+	pattern.WriteByte('^')
+	group := 0
+	last := 0
+	for _, loc := range placeholderPattern.FindAllStringIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		last = loc[1]
+
+		switch placeholder := template[loc[0]:loc[1]]; placeholder {
+		case PlaceholderPrefix:
+			pattern.WriteString(regexp.QuoteMeta(n.prefix))
+		case PlaceholderExt:
+			pattern.WriteString(regexp.QuoteMeta(n.ext))
+		case PlaceholderHostname:
+			pattern.WriteString(regexp.QuoteMeta(n.hostname))
+		case PlaceholderPID:
+			// Backups of previous processes are recognized too.
+			pattern.WriteString(`\d+`)
+		case PlaceholderTimestamp:
+			group++
+			n.timeIndex = group
+			pattern.WriteString(`(.+?)`)
+		case PlaceholderSeq:
+			group++
+			n.seqIndex = group
+			pattern.WriteString(`(\d+)`)
+		default:
+			return nil, fmt.Errorf("%w: unknown placeholder %s", ErrInvalidNamingTemplate, placeholder)
+		}
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString(`$`)
+
+	if n.timeIndex == -1 && n.seqIndex == -1 {
+		return nil, fmt.Errorf(
+			"%w: %s or %s is required", ErrInvalidNamingTemplate, PlaceholderTimestamp, PlaceholderSeq,
+		)
+	}
+	if strings.ContainsAny(template, `/\`) {
+		return nil, fmt.Errorf("%w: separators of path are not allowed", ErrInvalidNamingTemplate)
+	}
+
+	n.pattern = regexp.MustCompile(pattern.String())
+	return n, nil
+}
+
+// hasSeq reports whether backups are numbered.
+func (that *naming) hasSeq() bool {
+	return that.seqIndex != -1
+}
+
+// format returns the name of backup without directory.
+func (that *naming) format(t time.Time, seq int) string {
+	return strings.NewReplacer(
+		PlaceholderPrefix, that.prefix,
+		PlaceholderExt, that.ext,
+		PlaceholderTimestamp, t.Format(that.timeFormat),
+		PlaceholderSeq, strconv.Itoa(seq),
+		PlaceholderHostname, that.hostname,
+		PlaceholderPID, strconv.Itoa(os.Getpid()),
+	).Replace(that.template)
+}
+
+// duplicate returns the name of backup with the suffix of duplicate, e.g. "app-2006-01-02.log.1",
+// so backups with the same timestamp do not overwrite each other.
+func (that *naming) duplicate(name string, dup int) string {
+	if backup, ok := that.parse(name); ok && backup.dup != 0 {
+		name = strings.TrimSuffix(name, "."+strconv.Itoa(backup.dup))
+	}
+	return name + "." + strconv.Itoa(dup)
+}
+
+// backupName is the recognized name of backup.
+type backupName struct {
+	// base is the name of uncompressed backup.
+	base       string
+	timestamp  time.Time
+	seq        int
+	dup        int
+	compressed bool
+	temporary  bool
+}
+
+// parse recognizes the name of backup, that is compressed by any of known codecs,
+// and temporary files of archiving.
+func (that *naming) parse(name string) (backupName, bool) {
+	result := backupName{base: name}
+	if strings.HasSuffix(result.base, tmpExtension) {
+		result.base = strings.TrimSuffix(result.base, tmpExtension)
+		result.temporary = true
+	}
+	for _, compressed := range compressedExtensions {
+		if strings.HasSuffix(result.base, compressed) {
+			result.base = strings.TrimSuffix(result.base, compressed)
+			result.compressed = true
+			break
+		}
+	}
+
+	if that.match(result.base, &result) {
+		return result, true
+	}
+	if match := duplicatePattern.FindStringSubmatch(result.base); match != nil && that.match(match[1], &result) {
+		result.dup, _ = strconv.Atoi(match[2])
+		return result, true
+	}
+	return backupName{}, false
+}
+
+// match recognizes the name of backup by the template.
+func (that *naming) match(name string, result *backupName) bool {
+	match := that.pattern.FindStringSubmatch(name)
+	if match == nil {
+		return false
+	}
+
+	if that.timeIndex != -1 {
+		t, err := time.ParseInLocation(that.timeFormat, match[that.timeIndex], that.location)
+		if err != nil {
+			return false
+		}
+		result.timestamp = t
+	}
+	if that.seqIndex != -1 {
+		seq, err := strconv.Atoi(match[that.seqIndex])
+		if err != nil {
+			return false
+		}
+		result.seq = seq
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package logFileRotator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNaming(t *testing.T) {
+	type Test struct {
+		template string
+		name     string
+		parsed   string
+		seq      int
+		dup      int
+	}
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	pid := strconv.Itoa(os.Getpid())
+
+	tests := map[string]Test{
+		"Default": {
+			template: DefaultNamingTemplate,
+			name:     "app-2024-03-01T10-00-00.000.log",
+			parsed:   "app-2024-03-01T10-00-00.000.log.gz",
+		},
+		"Sequence": {
+			template: "{prefix}{ext}.{seq}",
+			name:     "app.log.7",
+			parsed:   "app.log.7.zst",
+			seq:      7,
+		},
+		"Duplicate": {
+			template: DefaultNamingTemplate,
+			name:     "app-2024-03-01T10-00-00.000.log",
+			parsed:   "app-2024-03-01T10-00-00.000.log.2.gz",
+			dup:      2,
+		},
+		"Duplicate of sequence": {
+			template: "{prefix}{ext}.{seq}",
+			name:     "app.log.7",
+			parsed:   "app.log.7.1",
+			seq:      7,
+			dup:      1,
+		},
+		"Duplicate without extension": {
+			template: "{prefix}-{timestamp}",
+			name:     "app-2024-03-01T10-00-00.000",
+			parsed:   "app-2024-03-01T10-00-00.000.3",
+			dup:      3,
+		},
+		"Hostname and PID": {
+			template: "{prefix}-{hostname}-{pid}-{timestamp}{ext}",
+			name:     "app-" + hostname + "-" + pid + "-2024-03-01T10-00-00.000.log",
+			parsed:   "app-" + hostname + "-1-2024-03-01T10-00-00.000.log",
+		},
+	}
+
+	now := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := newNaming(test.template, "/var/log/app.log", "2006-01-02T15-04-05.000", time.UTC)
+			require.NoError(t, err)
+			assert.Equal(t, test.name, n.format(now, test.seq))
+
+			backup, ok := n.parse(test.parsed)
+			require.True(t, ok)
+			assert.Equal(t, test.seq, backup.seq)
+			assert.Equal(t, test.dup, backup.dup)
+			if n.timeIndex != -1 {
+				assert.Equal(t, now, backup.timestamp)
+			}
+
+			_, ok = n.parse("app.log")
+			assert.False(t, ok)
+
+			duplicate := n.duplicate(n.duplicate(test.name, 1), 2)
+			assert.Equal(t, test.name+".2", duplicate)
+		})
+	}
+}
+
+func TestNamingInvalidTemplate(t *testing.T) {
+	templates := []string{
+		"{prefix}{ext}",
+		"{prefix}-{unknown}-{seq}",
+		"old/{prefix}-{seq}",
+	}
+
+	for _, template := range templates {
+		_, err := newNaming(template, "app.log", time.RFC3339, time.UTC)
+		assert.ErrorIs(t, err, ErrInvalidNamingTemplate, template)
+	}
+}
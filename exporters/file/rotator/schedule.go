@@ -0,0 +1,60 @@
+package logFileRotator
+
+import "time"
+
+// Schedule defines moments of time-based rotation.
+// Moments are calculated in the time zone of the engine (see Builder.WithLocalTime).
+type Schedule interface {
+	// Next returns the moment of rotation following t.
+	Next(t time.Time) time.Time
+}
+
+// ScheduleFunc is an adapter to allow the use of ordinary functions as Schedule.
+type ScheduleFunc func(t time.Time) time.Time
+
+func (that ScheduleFunc) Next(t time.Time) time.Time {
+	return that(t)
+}
+
+// Hourly rotates at the beginning of every hour.
+func Hourly() Schedule {
+	return ScheduleFunc(func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+	})
+}
+
+// DailyAt rotates every day at the given time.
+func DailyAt(hour, minute int) Schedule {
+	return ScheduleFunc(func(t time.Time) time.Time {
+		next := time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, t.Location())
+		if !next.After(t) {
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, hour, minute, 0, 0, t.Location())
+		}
+		return next
+	})
+}
+
+// Every rotates at multiples of interval since the zero time,
+// e.g. Every(15*time.Minute) rotates at :00, :15, :30 and :45.
+// The interval must be positive, otherwise Builder.Build returns ErrInvalidSchedule.
+func Every(interval time.Duration) Schedule {
+	return ScheduleFunc(func(t time.Time) time.Time {
+		return t.Truncate(interval).Add(interval)
+	})
+}
+
+// Clock is the source of time of the engine, that is replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (that systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (that systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,135 @@
+package otlpExporter
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type Builder struct {
+	exporter *Exporter
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		exporter: &Exporter{
+			client:       &http.Client{Timeout: 10 * time.Second},
+			headers:      make(map[string]string),
+			resource:     make(map[string]string),
+			scope:        "github.com/adverax/log",
+			batchSize:    512,
+			maxQueueSize: 2048,
+			interval:     5 * time.Second,
+			maxRetries:   3,
+			retryBackoff: 100 * time.Millisecond,
+		},
+	}
+}
+
+// WithEndpoint sets URL of the OTLP/HTTP logs endpoint,
+// e.g. "http://localhost:4318/v1/logs".
+func (that *Builder) WithEndpoint(endpoint string) *Builder {
+	that.exporter.endpoint = endpoint
+	return that
+}
+
+func (that *Builder) WithHTTPClient(client *http.Client) *Builder {
+	that.exporter.client = client
+	return that
+}
+
+func (that *Builder) WithHeader(key, value string) *Builder {
+	that.exporter.headers[key] = value
+	return that
+}
+
+// WithResource adds the attribute of the resource, e.g. "service.name".
+func (that *Builder) WithResource(key, value string) *Builder {
+	that.exporter.resource[key] = value
+	return that
+}
+
+func (that *Builder) WithServiceName(name string) *Builder {
+	return that.WithResource("service.name", name)
+}
+
+func (that *Builder) WithScope(scope string) *Builder {
+	that.exporter.scope = scope
+	return that
+}
+
+// WithBatchSize sets the number of records, that triggers sending of the batch.
+func (that *Builder) WithBatchSize(batchSize int) *Builder {
+	that.exporter.batchSize = batchSize
+	return that
+}
+
+// WithMaxQueueSize sets the number of records, that can wait for sending.
+// Records above the limit are dropped.
+func (that *Builder) WithMaxQueueSize(maxQueueSize int) *Builder {
+	that.exporter.maxQueueSize = maxQueueSize
+	return that
+}
+
+// WithInterval sets the period of sending incomplete batches.
+func (that *Builder) WithInterval(interval time.Duration) *Builder {
+	that.exporter.interval = interval
+	return that
+}
+
+// WithMaxRetries sets the number of retries of failed sending of the batch.
+// The batch is dropped, when retries are exhausted.
+func (that *Builder) WithMaxRetries(maxRetries int) *Builder {
+	that.exporter.maxRetries = maxRetries
+	return that
+}
+
+// WithRetryBackoff sets the delay before the first retry. It is doubled for every next retry.
+func (that *Builder) WithRetryBackoff(backoff time.Duration) *Builder {
+	that.exporter.retryBackoff = backoff
+	return that
+}
+
+func (that *Builder) Build() (*Exporter, error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+
+	that.exporter.start()
+	return that.exporter, nil
+}
+
+func (that *Builder) checkRequiredFields() error {
+	if that.exporter.endpoint == "" {
+		return ErrRequiredFieldEndpoint
+	}
+	if that.exporter.client == nil {
+		return ErrRequiredFieldHTTPClient
+	}
+	if that.exporter.batchSize <= 0 {
+		return ErrInvalidBatchSize
+	}
+	if that.exporter.maxQueueSize < that.exporter.batchSize {
+		return ErrInvalidMaxQueueSize
+	}
+	if that.exporter.interval <= 0 {
+		return ErrInvalidInterval
+	}
+	if that.exporter.maxRetries < 0 {
+		return ErrInvalidMaxRetries
+	}
+	if that.exporter.retryBackoff <= 0 {
+		return ErrInvalidRetryBackoff
+	}
+	return nil
+}
+
+var (
+	ErrRequiredFieldEndpoint   = errors.New("endpoint is required")
+	ErrRequiredFieldHTTPClient = errors.New("http client is required")
+	ErrInvalidBatchSize        = errors.New("batch size must be positive")
+	ErrInvalidMaxQueueSize     = errors.New("max queue size must not be less than batch size")
+	ErrInvalidInterval         = errors.New("interval must be positive")
+	ErrInvalidMaxRetries       = errors.New("max retries must not be negative")
+	ErrInvalidRetryBackoff     = errors.New("retry backoff must be positive")
+)
@@ -0,0 +1,246 @@
+package otlpExporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/adverax/log"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Exporter converts entries to the OTLP logs data model and sends them
+// in batches to the collector over OTLP/HTTP with JSON encoding.
+type Exporter struct {
+	endpoint     string
+	client       *http.Client
+	headers      map[string]string
+	resource     map[string]string
+	scope        string
+	batchSize    int
+	maxQueueSize int
+	interval     time.Duration
+	maxRetries   int
+	retryBackoff time.Duration
+
+	mu      sync.Mutex
+	records []logRecord
+	closed  bool
+	sending sync.Mutex
+	full    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+
+	dropped atomic.Uint64
+}
+
+func (that *Exporter) start() {
+	that.full = make(chan struct{}, 1)
+	that.stop = make(chan struct{})
+	that.done = make(chan struct{})
+	go that.run()
+}
+
+func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
+	record := newLogRecord(entry, time.Now())
+
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	if that.closed || len(that.records) >= that.maxQueueSize {
+		that.dropped.Add(1)
+		return
+	}
+
+	that.records = append(that.records, record)
+	if len(that.records) >= that.batchSize {
+		select {
+		case that.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush sends all queued records.
+func (that *Exporter) Flush(ctx context.Context) error {
+	for {
+		that.mu.Lock()
+		empty := len(that.records) == 0
+		that.mu.Unlock()
+		if empty {
+			return nil
+		}
+
+		if err := that.send(ctx, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// Close stops the background sending and sends the remaining records.
+func (that *Exporter) Close(ctx context.Context) error {
+	that.mu.Lock()
+	if that.closed {
+		that.mu.Unlock()
+		return nil
+	}
+	that.closed = true
+	that.mu.Unlock()
+
+	close(that.stop)
+	select {
+	case <-that.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return that.Flush(ctx)
+}
+
+// Dropped returns the number of records dropped because of the queue overflow
+// or failed sending.
+func (that *Exporter) Dropped() uint64 {
+	return that.dropped.Load()
+}
+
+func (that *Exporter) run() {
+	defer close(that.done)
+
+	ticker := time.NewTicker(that.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-that.stop:
+			return
+		case <-ticker.C:
+		case <-that.full:
+		}
+
+		if err := that.send(context.Background(), that.stop); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to export logs, %v\n", err)
+		}
+	}
+}
+
+// send sends one batch of queued records. The batch is kept in the queue until
+// it is sent, and sending is retried with exponential backoff. The batch is dropped,
+// when retries are exhausted. Retries are interrupted by the context or by closing
+// of the interrupt channel, that is nil for Flush, so the final flush of Close is retried too.
+func (that *Exporter) send(ctx context.Context, interrupt <-chan struct{}) error {
+	that.sending.Lock()
+	defer that.sending.Unlock()
+
+	// Records are appended to the queue only, so the batch remains at its head.
+	that.mu.Lock()
+	n := len(that.records)
+	if n > that.batchSize {
+		n = that.batchSize
+	}
+	batch := make([]logRecord, n)
+	copy(batch, that.records)
+	that.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	request := that.makeRequest(batch)
+	backoff := that.retryBackoff
+	err := that.post(ctx, request)
+	for attempt := 0; err != nil && attempt < that.maxRetries; attempt++ {
+		if !wait(ctx, interrupt, backoff) {
+			// The batch is sent by Close.
+			return err
+		}
+		backoff *= 2
+		err = that.post(ctx, request)
+	}
+
+	that.mu.Lock()
+	that.records = append(that.records[:0], that.records[n:]...)
+	that.mu.Unlock()
+
+	if err != nil {
+		that.dropped.Add(uint64(n))
+		return fmt.Errorf("dropped %d records after %d retries, %w", n, that.maxRetries, err)
+	}
+	return nil
+}
+
+// wait waits for the delay before the next attempt. It reports false, when the context
+// is done or the interrupt channel is closed.
+func wait(ctx context.Context, interrupt <-chan struct{}, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-interrupt:
+		return false
+	}
+}
+
+func (that *Exporter) makeRequest(batch []logRecord) *exportRequest {
+	keys := make([]string, 0, len(that.resource))
+	for k := range that.resource {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attributes := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		attributes = append(attributes, keyValue{Key: k, Value: stringValue(that.resource[k])})
+	}
+
+	return &exportRequest{
+		ResourceLogs: []resourceLogs{
+			{
+				Resource: resource{Attributes: attributes},
+				ScopeLogs: []scopeLogs{
+					{
+						Scope:      scope{Name: that.scope},
+						LogRecords: batch,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (that *Exporter) post(ctx context.Context, request *exportRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal logs, %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, that.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range that.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := that.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with status %s", resp.Status)
+	}
+	return nil
+}
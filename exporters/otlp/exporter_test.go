@@ -0,0 +1,173 @@
+package otlpExporter
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type collector struct {
+	sync.Mutex
+	requests []exportRequest
+}
+
+func (that *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request exportRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	that.Lock()
+	defer that.Unlock()
+	that.requests = append(that.requests, request)
+}
+
+func (that *collector) records() []logRecord {
+	that.Lock()
+	defer that.Unlock()
+
+	var records []logRecord
+	for _, request := range that.requests {
+		records = append(records, request.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+	}
+	return records
+}
+
+func TestExporter(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter, err := NewBuilder().
+		WithEndpoint(server.URL + "/v1/logs").
+		WithServiceName("api").
+		WithBatchSize(2).
+		WithInterval(time.Hour).
+		Build()
+	require.NoError(t, err)
+
+	logger, err := log.NewBuilder().
+		WithExporter(exporter).
+		Build()
+	require.NoError(t, err)
+
+	ctx := log.ContextWithFields(context.Background(), log.Fields{
+		log.FieldKeyTraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		log.FieldKeySpanID:     "00f067aa0ba902b7",
+		log.FieldKeyTraceFlags: "01",
+	})
+	logger.WithFields(log.Fields{"count": 3, "http": log.Fields{"method": "GET"}}).Info(ctx, "first")
+	logger.Warning(context.Background(), "second")
+	assert.Eventually(t, func() bool {
+		return len(c.records()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	logger.Error(context.Background(), "third")
+	require.NoError(t, logger.Close(context.Background()))
+
+	records := c.records()
+	require.Len(t, records, 3)
+	assert.Equal(t, "api", *c.requests[0].ResourceLogs[0].Resource.Attributes[0].Value.StringValue)
+
+	first := records[0]
+	assert.Equal(t, "first", *first.Body.StringValue)
+	assert.Equal(t, 9, first.SeverityNumber)
+	assert.Equal(t, "info", first.SeverityText)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", first.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", first.SpanID)
+	assert.Equal(t, uint32(1), first.Flags)
+	require.Len(t, first.Attributes, 2)
+	assert.Equal(t, "count", first.Attributes[0].Key)
+	assert.Equal(t, "3", *first.Attributes[0].Value.IntValue)
+	assert.Equal(t, "http", first.Attributes[1].Key)
+	assert.Equal(t, "method", first.Attributes[1].Value.KvlistValue.Values[0].Key)
+
+	assert.Equal(t, 13, records[1].SeverityNumber)
+	assert.Equal(t, 17, records[2].SeverityNumber)
+	assert.Equal(t, uint64(0), exporter.Dropped())
+}
+
+func TestExporterRetries(t *testing.T) {
+	type Test struct {
+		failures int
+		// closed skips Flush, so records are sent by Close.
+		closed  bool
+		records int
+		dropped uint64
+	}
+
+	tests := map[string]Test{
+		"Sent after retries": {
+			failures: 2,
+			records:  1,
+		},
+		"Dropped after retries": {
+			failures: 3,
+			dropped:  1,
+		},
+		"Sent after retries on Close": {
+			failures: 2,
+			closed:   true,
+			records:  1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &collector{}
+			var mu sync.Mutex
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				attempts++
+				failed := attempts <= test.failures
+				mu.Unlock()
+				if failed {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				c.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+
+			exporter, err := NewBuilder().
+				WithEndpoint(server.URL).
+				WithInterval(time.Hour).
+				WithMaxRetries(2).
+				WithRetryBackoff(time.Millisecond).
+				Build()
+			require.NoError(t, err)
+
+			logger, err := log.NewBuilder().WithExporter(exporter).Build()
+			require.NoError(t, err)
+
+			logger.Info(context.Background(), "message")
+			if !test.closed {
+				err = exporter.Flush(context.Background())
+				if test.dropped != 0 {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+			require.NoError(t, exporter.Close(context.Background()))
+
+			assert.Len(t, c.records(), test.records)
+			assert.Equal(t, test.dropped, exporter.Dropped())
+		})
+	}
+}
+
+func TestMakeAnyValueUint(t *testing.T) {
+	assert.Equal(t, "42", *makeAnyValue(uint64(42)).IntValue)
+	assert.Equal(t, "18446744073709551615", *makeAnyValue(uint64(math.MaxUint64)).StringValue)
+}
@@ -0,0 +1,263 @@
+package otlpExporter
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/adverax/log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// The types below follow the JSON encoding of the OTLP logs data model
+// (opentelemetry/proto/collector/logs/v1/logs_service.proto).
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber"`
+	SeverityText         string     `json:"severityText"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+	Flags                uint32     `json:"flags,omitempty"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+	IntValue    *string      `json:"intValue,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	BytesValue  *string      `json:"bytesValue,omitempty"`
+	ArrayValue  *arrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *kvlistValue `json:"kvlistValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+type kvlistValue struct {
+	Values []keyValue `json:"values"`
+}
+
+// Severity numbers of the OTLP logs data model.
+const (
+	severityTrace  = 1
+	severityDebug  = 5
+	severityInfo   = 9
+	severityWarn   = 13
+	severityError  = 17
+	severityFatal  = 21
+	severityFatal4 = 24
+)
+
+// SeverityNumber maps Level onto the OTLP severity number.
+func SeverityNumber(level log.Level) int {
+	switch level {
+	case log.TraceLevel:
+		return severityTrace
+	case log.DebugLevel:
+		return severityDebug
+	case log.InfoLevel:
+		return severityInfo
+	case log.WarnLevel:
+		return severityWarn
+	case log.ErrorLevel:
+		return severityError
+	case log.FatalLevel:
+		return severityFatal
+	default:
+		return severityFatal4
+	}
+}
+
+func newLogRecord(entry *log.Entry, observed time.Time) logRecord {
+	record := logRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(observed.UnixNano(), 10),
+		SeverityNumber:       SeverityNumber(entry.Level),
+		SeverityText:         entry.Level.String(),
+		Body:                 stringValue(entry.Message),
+	}
+
+	data := entry.Fields()
+	if traceID, ok := data[log.FieldKeyTraceID].(string); ok && isHexID(traceID, 16) {
+		record.TraceID = traceID
+	}
+	if spanID, ok := data[log.FieldKeySpanID].(string); ok && isHexID(spanID, 8) {
+		record.SpanID = spanID
+	}
+	if flags, ok := data[log.FieldKeyTraceFlags].(string); ok {
+		if v, err := strconv.ParseUint(flags, 16, 8); err == nil {
+			record.Flags = uint32(v)
+		}
+	}
+
+	record.Attributes = makeKeyValues(data, func(key string) bool {
+		switch key {
+		case log.FieldKeyTraceID:
+			return record.TraceID == ""
+		case log.FieldKeySpanID:
+			return record.SpanID == ""
+		case log.FieldKeyTraceFlags:
+			return record.Flags == 0
+		default:
+			return true
+		}
+	})
+
+	if entry.LogErr != "" {
+		record.Attributes = append(record.Attributes, keyValue{Key: log.FieldKeyLoggerError, Value: stringValue(entry.LogErr)})
+	}
+	if entry.HasCaller() {
+		record.Attributes = append(record.Attributes,
+			keyValue{Key: "code.function", Value: stringValue(entry.Caller.Function)},
+			keyValue{Key: "code.filepath", Value: stringValue(entry.Caller.File)},
+			keyValue{Key: "code.lineno", Value: intValue(int64(entry.Caller.Line))},
+		)
+	}
+	if entry.Stack != nil {
+		record.Attributes = append(record.Attributes, keyValue{Key: "exception.stacktrace", Value: stringValue(entry.Stack.String())})
+	}
+
+	return record
+}
+
+func makeKeyValues(data map[string]interface{}, filter func(key string) bool) []keyValue {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		if filter == nil || filter(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	values := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, keyValue{Key: k, Value: makeAnyValue(data[k])})
+	}
+	return values
+}
+
+// uintValue encodes values above math.MaxInt64 as strings, because OTLP integers are signed.
+func uintValue(v uint64) anyValue {
+	if v > math.MaxInt64 {
+		return stringValue(strconv.FormatUint(v, 10))
+	}
+	return intValue(int64(v))
+}
+
+func makeAnyValue(value interface{}) anyValue {
+	switch v := value.(type) {
+	case nil:
+		return anyValue{}
+	case string:
+		return stringValue(v)
+	case bool:
+		return anyValue{BoolValue: &v}
+	case int:
+		return intValue(int64(v))
+	case int8:
+		return intValue(int64(v))
+	case int16:
+		return intValue(int64(v))
+	case int32:
+		return intValue(int64(v))
+	case int64:
+		return intValue(v)
+	case uint:
+		return uintValue(uint64(v))
+	case uint8:
+		return intValue(int64(v))
+	case uint16:
+		return intValue(int64(v))
+	case uint32:
+		return intValue(int64(v))
+	case uint64:
+		return uintValue(v)
+	case float32:
+		f := float64(v)
+		return anyValue{DoubleValue: &f}
+	case float64:
+		return anyValue{DoubleValue: &v}
+	case []byte:
+		s := base64.StdEncoding.EncodeToString(v)
+		return anyValue{BytesValue: &s}
+	case time.Time:
+		return stringValue(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return stringValue(v.String())
+	case error:
+		return stringValue(v.Error())
+	case log.Fields:
+		return anyValue{KvlistValue: &kvlistValue{Values: makeKeyValues(v, nil)}}
+	case map[string]interface{}:
+		return anyValue{KvlistValue: &kvlistValue{Values: makeKeyValues(v, nil)}}
+	case []interface{}:
+		values := make([]anyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, makeAnyValue(item))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	case fmt.Stringer:
+		return stringValue(v.String())
+	default:
+		return stringValue(fmt.Sprint(v))
+	}
+}
+
+func stringValue(s string) anyValue {
+	return anyValue{StringValue: &s}
+}
+
+func intValue(i int64) anyValue {
+	s := strconv.FormatInt(i, 10)
+	return anyValue{IntValue: &s}
+}
+
+func isHexID(s string, size int) bool {
+	if len(s) != 2*size {
+		return false
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return false
+	}
+	for _, c := range b {
+		if c != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package samplerExporter
+
+import (
+	"errors"
+	"github.com/adverax/log"
+	"time"
+)
+
+type Builder struct {
+	exporter   *Exporter
+	first      int
+	thereafter int
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		exporter: &Exporter{
+			tick:         time.Second,
+			sampledLevel: log.InfoLevel,
+			limits:       make(map[log.Level]*bucket),
+			counters:     make(map[counterKey]*counter),
+			now:          time.Now,
+		},
+	}
+}
+
+func (that *Builder) WithExporter(exporter log.Exporter) *Builder {
+	that.exporter.exporter = exporter
+	return that
+}
+
+// WithSampling enables sampling: within each tick the first entries with
+// the same level and message template are exported, then every thereafter-th.
+// Zero thereafter drops all entries after the first ones.
+func (that *Builder) WithSampling(tick time.Duration, first, thereafter int) *Builder {
+	that.exporter.sampling = true
+	that.exporter.tick = tick
+	that.first = first
+	that.thereafter = thereafter
+	return that
+}
+
+// WithSampledLevel sets the most severe level, that is sampled.
+// More severe entries are always exported. Default is log.InfoLevel.
+func (that *Builder) WithSampledLevel(level log.Level) *Builder {
+	that.exporter.sampledLevel = level
+	return that
+}
+
+// WithRateLimit limits the rate of entries with the level by the token bucket,
+// that holds up to burst tokens and is refilled with rate tokens per second.
+func (that *Builder) WithRateLimit(level log.Level, rate float64, burst int) *Builder {
+	that.exporter.limits[level] = &bucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+	return that
+}
+
+// WithSummaryInterval sets the period of reporting of suppressed entries.
+// Default is the sampling tick.
+func (that *Builder) WithSummaryInterval(interval time.Duration) *Builder {
+	that.exporter.summaryInterval = interval
+	return that
+}
+
+func (that *Builder) WithClock(now func() time.Time) *Builder {
+	that.exporter.now = now
+	return that
+}
+
+func (that *Builder) Build() (*Exporter, error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+
+	that.exporter.first = uint64(that.first)
+	that.exporter.thereafter = uint64(that.thereafter)
+	if that.exporter.summaryInterval == 0 {
+		that.exporter.summaryInterval = that.exporter.tick
+	}
+	that.exporter.start()
+	return that.exporter, nil
+}
+
+func (that *Builder) checkRequiredFields() error {
+	if that.exporter.exporter == nil {
+		return ErrRequiredFieldExporter
+	}
+	if that.exporter.tick <= 0 {
+		return ErrInvalidTick
+	}
+	if that.first < 0 || that.thereafter < 0 {
+		return ErrInvalidSampling
+	}
+	if that.exporter.summaryInterval < 0 {
+		return ErrInvalidSummaryInterval
+	}
+	if that.exporter.now == nil {
+		return ErrRequiredFieldClock
+	}
+	for _, limit := range that.exporter.limits {
+		if limit.rate <= 0 || limit.burst <= 0 {
+			return ErrInvalidRateLimit
+		}
+	}
+	return nil
+}
+
+var (
+	ErrRequiredFieldExporter  = errors.New("exporter is required")
+	ErrRequiredFieldClock     = errors.New("clock is required")
+	ErrInvalidTick            = errors.New("tick must be positive")
+	ErrInvalidRateLimit       = errors.New("rate and burst of rate limit must be positive")
+	ErrInvalidSampling        = errors.New("first and thereafter of sampling must not be negative")
+	ErrInvalidSummaryInterval = errors.New("summary interval must not be negative")
+)
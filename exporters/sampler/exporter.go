@@ -0,0 +1,217 @@
+package samplerExporter
+
+import (
+	"context"
+	"github.com/adverax/log"
+	"sync"
+	"time"
+)
+
+const (
+	FieldKeySuppressed     = "suppressed"
+	FieldKeySampledMessage = "sampled_msg"
+)
+
+const SummaryMessage = "entries suppressed by sampling"
+
+type counterKey struct {
+	level    log.Level
+	template string
+}
+
+type counter struct {
+	start      time.Time
+	count      uint64
+	suppressed uint64
+	logger     *log.Log
+}
+
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket.
+func (that *bucket) allow(now time.Time) bool {
+	if !that.last.IsZero() {
+		that.tokens += now.Sub(that.last).Seconds() * that.rate
+		if that.tokens > that.burst {
+			that.tokens = that.burst
+		}
+	}
+	that.last = now
+
+	if that.tokens < 1 {
+		return false
+	}
+	that.tokens--
+	return true
+}
+
+// Exporter samples and rate limits entries before passing them to the wrapped exporter.
+// Entries are counted independently by level and message template.
+// Suppressed entries are reported periodically by summary entries.
+type Exporter struct {
+	exporter        log.Exporter
+	sampling        bool
+	tick            time.Duration
+	first           uint64
+	thereafter      uint64
+	sampledLevel    log.Level
+	limits          map[log.Level]*bucket
+	summaryInterval time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	counters map[counterKey]*counter
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func (that *Exporter) start() {
+	that.stop = make(chan struct{})
+	that.done = make(chan struct{})
+	go that.run()
+}
+
+func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
+	now := that.now()
+
+	that.mu.Lock()
+	key := counterKey{level: entry.Level, template: templateOf(entry)}
+	c, ok := that.counters[key]
+	if !ok {
+		c = &counter{start: now}
+		that.counters[key] = c
+	}
+
+	var summary *log.Entry
+	if now.Sub(c.start) >= that.tick {
+		summary = that.summarize(key, c, now)
+		c.start = now
+		c.count = 0
+	}
+
+	c.logger = entry.Logger
+	allowed := that.sample(entry, c) && that.allow(entry.Level, now)
+	if !allowed {
+		c.suppressed++
+	}
+	that.mu.Unlock()
+
+	if summary != nil {
+		that.exporter.Export(ctx, summary)
+	}
+	if allowed {
+		that.exporter.Export(ctx, entry)
+	}
+}
+
+// Flush reports suppressed entries and flushes the wrapped exporter.
+func (that *Exporter) Flush(ctx context.Context) error {
+	that.report(ctx, false)
+	return log.Flush(ctx, that.exporter)
+}
+
+// Close stops periodic reporting, reports suppressed entries
+// and closes the wrapped exporter.
+func (that *Exporter) Close(ctx context.Context) error {
+	that.stopOnce.Do(func() {
+		close(that.stop)
+	})
+	select {
+	case <-that.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	that.report(ctx, false)
+	return log.Close(ctx, that.exporter)
+}
+
+func (that *Exporter) run() {
+	defer close(that.done)
+
+	ticker := time.NewTicker(that.summaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-that.stop:
+			return
+		case <-ticker.C:
+			that.report(context.Background(), true)
+		}
+	}
+}
+
+// report exports summaries of all suppressed entries.
+// When expire is set, counters of finished ticks are removed.
+func (that *Exporter) report(ctx context.Context, expire bool) {
+	now := that.now()
+
+	that.mu.Lock()
+	var summaries []*log.Entry
+	for key, c := range that.counters {
+		if summary := that.summarize(key, c, now); summary != nil {
+			summaries = append(summaries, summary)
+		}
+		if expire && now.Sub(c.start) >= that.tick {
+			delete(that.counters, key)
+		}
+	}
+	that.mu.Unlock()
+
+	for _, summary := range summaries {
+		that.exporter.Export(ctx, summary)
+	}
+}
+
+// summarize returns the summary entry and resets the number of suppressed entries.
+func (that *Exporter) summarize(key counterKey, c *counter, now time.Time) *log.Entry {
+	if c.suppressed == 0 {
+		return nil
+	}
+
+	summary := &log.Entry{
+		Logger:  c.logger,
+		Time:    now,
+		Level:   key.level,
+		Message: SummaryMessage,
+		Data: log.Fields{
+			FieldKeySampledMessage: key.template,
+			FieldKeySuppressed:     c.suppressed,
+		},
+	}
+	c.suppressed = 0
+	return summary
+}
+
+func (that *Exporter) sample(entry *log.Entry, c *counter) bool {
+	c.count++
+	if !that.sampling || entry.Level < that.sampledLevel {
+		return true
+	}
+	if c.count <= that.first {
+		return true
+	}
+	return that.thereafter > 0 && (c.count-that.first)%that.thereafter == 0
+}
+
+func (that *Exporter) allow(level log.Level, now time.Time) bool {
+	limit, ok := that.limits[level]
+	if !ok {
+		return true
+	}
+	return limit.allow(now)
+}
+
+func templateOf(entry *log.Entry) string {
+	if entry.Template != "" {
+		return entry.Template
+	}
+	return entry.Message
+}
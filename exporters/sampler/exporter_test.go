@@ -0,0 +1,109 @@
+package samplerExporter
+
+import (
+	"context"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type memoryExporter struct {
+	entries []*log.Entry
+}
+
+func (that *memoryExporter) Export(ctx context.Context, entry *log.Entry) {
+	that.entries = append(that.entries, entry.Snapshot())
+}
+
+func (that *memoryExporter) messages() []string {
+	var messages []string
+	for _, entry := range that.entries {
+		messages = append(messages, entry.Message)
+	}
+	that.entries = nil
+	return messages
+}
+
+func TestExporter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := &memoryExporter{}
+
+	exporter, err := NewBuilder().
+		WithExporter(target).
+		WithSampling(time.Second, 2, 3).
+		WithRateLimit(log.ErrorLevel, 1, 2).
+		WithSummaryInterval(time.Hour).
+		WithClock(func() time.Time { return now }).
+		Build()
+	require.NoError(t, err)
+
+	logger, err := log.NewBuilder().
+		WithLevel(log.DebugLevel).
+		WithExporter(exporter).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 1; i <= 9; i++ {
+		logger.Infof(ctx, "request %d", i)
+		logger.Info(ctx, "other")
+	}
+	assert.Equal(t, []string{
+		"request 1", "other",
+		"request 2", "other",
+		"request 5", "other",
+		"request 8", "other",
+	}, target.messages())
+
+	now = now.Add(time.Second)
+	logger.Infof(ctx, "request %d", 10)
+	require.Len(t, target.entries, 2)
+	assert.Equal(t, SummaryMessage, target.entries[0].Message)
+	assert.Equal(t, log.Fields{FieldKeySampledMessage: "request %d", FieldKeySuppressed: uint64(5)}, target.entries[0].Data)
+	assert.Equal(t, "request 10", target.entries[1].Message)
+	target.entries = nil
+
+	for i := 0; i < 3; i++ {
+		logger.Error(ctx, "failure")
+	}
+	assert.Equal(t, []string{"failure", "failure"}, target.messages())
+
+	now = now.Add(time.Second)
+	logger.Error(ctx, "failure")
+	assert.Equal(t, []string{SummaryMessage, "failure"}, target.messages())
+
+	require.NoError(t, logger.Close(ctx))
+	require.Len(t, target.entries, 1)
+	assert.Equal(t, log.Fields{FieldKeySampledMessage: "other", FieldKeySuppressed: uint64(5)}, target.entries[0].Data)
+}
+
+func TestBuilderValidation(t *testing.T) {
+	type Test struct {
+		builder *Builder
+		err     error
+	}
+
+	tests := map[string]Test{
+		"Negative first": {
+			builder: NewBuilder().WithSampling(time.Second, -1, 3),
+			err:     ErrInvalidSampling,
+		},
+		"Negative thereafter": {
+			builder: NewBuilder().WithSampling(time.Second, 2, -1),
+			err:     ErrInvalidSampling,
+		},
+		"Negative summary interval": {
+			builder: NewBuilder().WithSummaryInterval(-time.Second),
+			err:     ErrInvalidSummaryInterval,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := test.builder.WithExporter(&memoryExporter{}).Build()
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package slogExporter
+
+import (
+	"context"
+	"fmt"
+	"github.com/adverax/log"
+	"log/slog"
+	"os"
+	"sort"
+)
+
+// Exporter forwards entries to an arbitrary slog.Handler.
+type Exporter struct {
+	handler slog.Handler
+}
+
+func New(handler slog.Handler) *Exporter {
+	return &Exporter{
+		handler: handler,
+	}
+}
+
+func (that *Exporter) Export(ctx context.Context, entry *log.Entry) {
+	level := log.LevelToSlog(entry.Level)
+	if !that.handler.Enabled(ctx, level) {
+		return
+	}
+
+	var pc uintptr
+	if entry.HasCaller() {
+		pc = entry.Caller.PC
+	}
+
+	record := slog.NewRecord(entry.Time, level, entry.Message, pc)
+	record.AddAttrs(makeAttrs(entry.Fields())...)
+	if entry.LogErr != "" {
+		record.AddAttrs(slog.String(log.FieldKeyLoggerError, entry.LogErr))
+	}
+
+	if err := that.handler.Handle(ctx, record); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+}
+
+func makeAttrs(data map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, makeAttr(k, data[k]))
+	}
+	return attrs
+}
+
+func makeAttr(key string, value interface{}) slog.Attr {
+	switch v := value.(type) {
+	case log.Fields:
+		return slog.Attr{Key: key, Value: slog.GroupValue(makeAttrs(v)...)}
+	case map[string]interface{}:
+		return slog.Attr{Key: key, Value: slog.GroupValue(makeAttrs(v)...)}
+	default:
+		return slog.Any(key, v)
+	}
+}
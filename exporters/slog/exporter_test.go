@@ -0,0 +1,32 @@
+package slogExporter
+
+import (
+	"bytes"
+	"context"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestExporter(t *testing.T) {
+	var out bytes.Buffer
+	handler := slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})
+
+	logger, err := log.NewBuilder().
+		WithExporter(New(handler)).
+		WithHook(log.HookFunc(func(ctx context.Context, entry *log.Entry) error {
+			entry.Time = time.Time{}
+			return nil
+		})).
+		Build()
+	require.NoError(t, err)
+
+	logger.
+		WithFields(log.Fields{"key": "value", "http": log.Fields{"method": "GET"}}).
+		Warning(context.Background(), "Hello, World!")
+
+	assert.Equal(t, `{"level":"WARN","msg":"Hello, World!","http":{"method":"GET"},"key":"value"}`+"\n", out.String())
+}
@@ -48,6 +48,10 @@ func (that *Exporter) export(entry *log.Entry) {
 	}
 }
 
+func (that *Exporter) Close(ctx context.Context) error {
+	return that.out.Close()
+}
+
 func (that *Exporter) put(level log.Level, msg string) error {
 	switch level {
 	case log.TraceLevel:
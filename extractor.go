@@ -0,0 +1,70 @@
+package log
+
+import "context"
+
+type contextFieldsType int
+
+const contextFieldsKey contextFieldsType = 0
+
+// ContextExtractor extracts fields of the entry from the context,
+// e.g. trace identifiers or request metadata.
+type ContextExtractor interface {
+	Extract(ctx context.Context) Fields
+}
+
+type ContextExtractorFunc func(ctx context.Context) Fields
+
+func (fn ContextExtractorFunc) Extract(ctx context.Context) Fields {
+	return fn(ctx)
+}
+
+// ContextWithFields returns new context with fields, that are added to every entry
+// logged with it. Fields of the parent context are inherited.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	parent := FieldsFromContext(ctx)
+	if len(parent) != 0 {
+		merged := make(Fields, len(parent)+len(fields))
+		for k, v := range parent {
+			merged[k] = v
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		fields = merged
+	}
+
+	return context.WithValue(ctx, contextFieldsKey, fields)
+}
+
+// FieldsFromContext returns fields attached by ContextWithFields.
+func FieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(contextFieldsKey).(Fields)
+	return fields
+}
+
+// NewContextValueExtractor returns extractor, that copies context values
+// into fields. The map binds field names to context keys, e.g.
+//
+//	log.NewContextValueExtractor(map[string]interface{}{
+//		log.FieldKeyTraceID: traceIDKey,
+//	})
+func NewContextValueExtractor(keys map[string]interface{}) ContextExtractor {
+	return ContextExtractorFunc(func(ctx context.Context) Fields {
+		var fields Fields
+		for field, key := range keys {
+			if v := ctx.Value(key); v != nil {
+				if fields == nil {
+					fields = make(Fields, len(keys))
+				}
+				fields[field] = v
+			}
+		}
+		return fields
+	})
+}
+
+var fieldsExtractor = ContextExtractorFunc(FieldsFromContext)
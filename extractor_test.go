@@ -0,0 +1,41 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type testTraceKey struct{}
+
+func TestContextExtractor(t *testing.T) {
+	exporter := &myExporter{}
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		WithContextExtractor(NewContextValueExtractor(map[string]interface{}{
+			FieldKeyTraceID: testTraceKey{},
+		})).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "abc")
+	ctx = ContextWithFields(ctx, Fields{FieldKeyRequestID: "r1", FieldKeyUserID: "u1"})
+	ctx = ContextWithFields(ctx, Fields{FieldKeyUserID: "u2"})
+
+	entry := logger.WithField(FieldKeyRequestID, "r2")
+	entry.Info(ctx, "Hello, World!")
+
+	assert.Equal(t, Fields{
+		FieldKeyTraceID:   "abc",
+		FieldKeyRequestID: "r2",
+		FieldKeyUserID:    "u2",
+	}, exporter.entry.Data)
+
+	logger.Info(context.Background(), "Hello, World!")
+	assert.Empty(t, exporter.entry.Data)
+
+	entry.Info(context.Background(), "Hello, World!")
+	assert.Equal(t, Fields{FieldKeyRequestID: "r2"}, exporter.entry.Data)
+}
@@ -0,0 +1,235 @@
+package log
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// FieldType is the kind of value held by Field.
+type FieldType uint8
+
+const (
+	FieldTypeAny FieldType = iota
+	FieldTypeString
+	FieldTypeInt64
+	FieldTypeFloat64
+	FieldTypeBool
+	FieldTypeDuration
+	FieldTypeTime
+	FieldTypeError
+	FieldTypeObject
+)
+
+// Field is a typed field of an entry.
+// Unlike values of Fields, scalar values are stored without boxing,
+// so logging them does not allocate.
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	String    string
+	Interface interface{}
+}
+
+// ObjectEncoder is implemented by encoders of formatters, so objects
+// are encoded without intermediate maps.
+type ObjectEncoder interface {
+	AddString(key, value string)
+	AddInt64(key string, value int64)
+	AddFloat64(key string, value float64)
+	AddBool(key string, value bool)
+	AddDuration(key string, value time.Duration)
+	AddTime(key string, value time.Time)
+	AddAny(key string, value interface{}) error
+	AddObject(key string, value ObjectMarshaler) error
+}
+
+// ObjectMarshaler is implemented by values logged by Object.
+type ObjectMarshaler interface {
+	MarshalLogObject(encoder ObjectEncoder) error
+}
+
+// ObjectMarshalerFunc is an adapter to allow the use of ordinary functions as ObjectMarshaler.
+type ObjectMarshalerFunc func(encoder ObjectEncoder) error
+
+func (that ObjectMarshalerFunc) MarshalLogObject(encoder ObjectEncoder) error {
+	return that(encoder)
+}
+
+func String(key, value string) Field {
+	return Field{Key: key, Type: FieldTypeString, String: value}
+}
+
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: FieldTypeInt64, Integer: value}
+}
+
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FieldTypeFloat64, Integer: int64(math.Float64bits(value))}
+}
+
+func Bool(key string, value bool) Field {
+	var integer int64
+	if value {
+		integer = 1
+	}
+	return Field{Key: key, Type: FieldTypeBool, Integer: integer}
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: FieldTypeDuration, Integer: int64(value)}
+}
+
+// Time stores the time as nanoseconds and location, unless it is out of range of nanoseconds.
+func Time(key string, value time.Time) Field {
+	if value.Before(minTimeNano) || value.After(maxTimeNano) {
+		return Field{Key: key, Type: FieldTypeTime, Interface: value}
+	}
+	return Field{Key: key, Type: FieldTypeTime, Integer: value.UnixNano(), Interface: value.Location()}
+}
+
+// Err returns the field of error with key ErrorKey.
+func Err(err error) Field {
+	return NamedErr(ErrorKey, err)
+}
+
+func NamedErr(key string, err error) Field {
+	if err == nil {
+		return Any(key, nil)
+	}
+	return Field{Key: key, Type: FieldTypeError, Interface: err}
+}
+
+func Object(key string, value ObjectMarshaler) Field {
+	return Field{Key: key, Type: FieldTypeObject, Interface: value}
+}
+
+// Any returns the field of arbitrary value. Formatters encode it by reflection.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Type: FieldTypeAny, Interface: value}
+}
+
+var (
+	minTimeNano = time.Unix(0, math.MinInt64)
+	maxTimeNano = time.Unix(0, math.MaxInt64)
+)
+
+func (that Field) Float64() float64 {
+	return math.Float64frombits(uint64(that.Integer))
+}
+
+func (that Field) Bool() bool {
+	return that.Integer != 0
+}
+
+func (that Field) Duration() time.Duration {
+	return time.Duration(that.Integer)
+}
+
+func (that Field) Time() time.Time {
+	switch v := that.Interface.(type) {
+	case time.Time:
+		return v
+	case *time.Location:
+		return time.Unix(0, that.Integer).In(v)
+	default:
+		return time.Unix(0, that.Integer)
+	}
+}
+
+// Value returns the value of field in the form used by Fields.
+func (that Field) Value() interface{} {
+	switch that.Type {
+	case FieldTypeString:
+		return that.String
+	case FieldTypeInt64:
+		return that.Integer
+	case FieldTypeFloat64:
+		return that.Float64()
+	case FieldTypeBool:
+		return that.Bool()
+	case FieldTypeDuration:
+		return that.Duration()
+	case FieldTypeTime:
+		return that.Time()
+	case FieldTypeObject:
+		encoder := make(fieldsEncoder)
+		if err := that.Interface.(ObjectMarshaler).MarshalLogObject(encoder); err != nil {
+			return fmt.Sprintf("!ERROR: %v", err)
+		}
+		return Fields(encoder)
+	default:
+		return that.Interface
+	}
+}
+
+// AddTo adds the field to encoder.
+func (that Field) AddTo(encoder ObjectEncoder) error {
+	switch that.Type {
+	case FieldTypeString:
+		encoder.AddString(that.Key, that.String)
+	case FieldTypeInt64:
+		encoder.AddInt64(that.Key, that.Integer)
+	case FieldTypeFloat64:
+		encoder.AddFloat64(that.Key, that.Float64())
+	case FieldTypeBool:
+		encoder.AddBool(that.Key, that.Bool())
+	case FieldTypeDuration:
+		encoder.AddDuration(that.Key, that.Duration())
+	case FieldTypeTime:
+		encoder.AddTime(that.Key, that.Time())
+	case FieldTypeError:
+		encoder.AddString(that.Key, that.Interface.(error).Error())
+	case FieldTypeObject:
+		return encoder.AddObject(that.Key, that.Interface.(ObjectMarshaler))
+	default:
+		return encoder.AddAny(that.Key, that.Interface)
+	}
+	return nil
+}
+
+// fieldsEncoder encodes objects into Fields for formatters, that work with Fields.
+type fieldsEncoder Fields
+
+func (that fieldsEncoder) AddString(key, value string) {
+	that[key] = value
+}
+
+func (that fieldsEncoder) AddInt64(key string, value int64) {
+	that[key] = value
+}
+
+func (that fieldsEncoder) AddFloat64(key string, value float64) {
+	that[key] = value
+}
+
+func (that fieldsEncoder) AddBool(key string, value bool) {
+	that[key] = value
+}
+
+func (that fieldsEncoder) AddDuration(key string, value time.Duration) {
+	that[key] = value
+}
+
+func (that fieldsEncoder) AddTime(key string, value time.Time) {
+	that[key] = value
+}
+
+func (that fieldsEncoder) AddAny(key string, value interface{}) error {
+	that[key] = value
+	return nil
+}
+
+func (that fieldsEncoder) AddObject(key string, value ObjectMarshaler) error {
+	encoder := make(fieldsEncoder)
+	if err := value.MarshalLogObject(encoder); err != nil {
+		return err
+	}
+	that[key] = Fields(encoder)
+	return nil
+}
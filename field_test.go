@@ -0,0 +1,103 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFieldValue(t *testing.T) {
+	type Test struct {
+		name     string
+		field    Field
+		expected interface{}
+	}
+
+	err := errors.New("failure")
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	tests := []Test{
+		{name: "string", field: String("k", "v"), expected: "v"},
+		{name: "int64", field: Int64("k", -42), expected: int64(-42)},
+		{name: "float64", field: Float64("k", 0.25), expected: 0.25},
+		{name: "bool", field: Bool("k", true), expected: true},
+		{name: "duration", field: Duration("k", time.Minute), expected: time.Minute},
+		{name: "time", field: Time("k", tm), expected: tm},
+		{name: "zero time", field: Time("k", time.Time{}), expected: time.Time{}},
+		{name: "error", field: Err(err), expected: err},
+		{name: "nil error", field: Err(nil), expected: nil},
+		{
+			name: "object",
+			field: Object("k", ObjectMarshalerFunc(func(encoder ObjectEncoder) error {
+				encoder.AddString("name", "bob")
+				return encoder.AddObject("inner", ObjectMarshalerFunc(func(encoder ObjectEncoder) error {
+					encoder.AddInt64("age", 42)
+					return nil
+				}))
+			})),
+			expected: Fields{"name": "bob", "inner": Fields{"age": int64(42)}},
+		},
+		{name: "any", field: Any("k", []int{1}), expected: []int{1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.field.Value())
+		})
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	exporter := &myExporter{}
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	failure := errors.New("failure")
+	logger.WithField("key", "old").(FieldsLogger).InfoFields(ctx, "Hello, World!", String("key", "new"), Err(failure))
+	assert.Equal(t, "Hello, World!", exporter.entry.Message)
+	assert.Equal(t, Fields{"key": "old"}, exporter.entry.Data)
+	assert.Equal(t, Fields{"key": "new", ErrorKey: failure}, exporter.entry.Fields())
+	assert.Equal(t, failure, exporter.entry.ErrorValue())
+
+	snapshot := exporter.entry.Snapshot()
+	logger.With(Fields{"a": 1}).WarningFields(ctx, "Hello, World2!", Int("b", 2))
+	assert.Equal(t, Fields{"a": 1, "b": int64(2)}, exporter.entry.Fields())
+	assert.Equal(t, Fields{"key": "new", ErrorKey: failure}, snapshot.Fields())
+
+	logger.DebugFields(ctx, "skipped")
+	assert.Equal(t, WarnLevel, exporter.entry.Level)
+}
+
+func TestLogFieldsHooks(t *testing.T) {
+	exporter := &myExporter{}
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		WithHook(HookFunc(func(ctx context.Context, entry *Entry) error {
+			entry.Data["hook"] = entry.Data["key"]
+			return nil
+		})).
+		Build()
+	require.NoError(t, err)
+
+	logger.InfoFields(context.Background(), "Hello, World!", String("key", "value"))
+	assert.Equal(t, Fields{"key": "value", "hook": "value"}, exporter.entry.Data)
+	assert.Empty(t, exporter.entry.TypedFields)
+}
+
+func BenchmarkLogFields(b *testing.B) {
+	logger := NewDummyLogger()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.InfoFields(ctx, "Hello, World!", String("request_id", "r1"), Int64("user_id", 42), String("path", "/api"))
+	}
+}
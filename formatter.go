@@ -1,5 +1,10 @@
 package log
 
+import (
+	"strconv"
+	"strings"
+)
+
 const (
 	prefix = "fields."
 )
@@ -18,6 +23,15 @@ const (
 	FieldKeyData        = "data"
 	FieldKeyDuration    = "duration"
 	FieldKeyTraceID     = "trace_id"
+	FieldKeySpanID      = "span_id"
+	FieldKeyTraceFlags  = "trace_flags"
+	FieldKeyRequestID   = "request_id"
+	FieldKeyUserID      = "user_id"
+	FieldKeyCaller      = "caller"
+	FieldKeyFunc        = "func"
+	FieldKeyLogger      = "logger"
+	FieldKeyStack       = "stack"
+	FieldKeyErrors      = "errors"
 )
 
 const (
@@ -38,6 +52,28 @@ type FieldKey string
 
 type FieldMap map[FieldKey]string
 
+// FormatLevel returns the name of level truncated to 4 characters, if truncate is set,
+// and padded by spaces to the length of the longest name, if pad is set.
+func FormatLevel(level Level, truncate, pad bool) string {
+	text := level.String()
+	width := maxLevelLength
+	if truncate {
+		width = 4
+		if len(text) > width {
+			text = text[:width]
+		}
+	}
+	if pad && len(text) < width {
+		text += strings.Repeat(" ", width-len(text))
+	}
+	return text
+}
+
+// FormatCaller returns the location of the caller as "file:line".
+func FormatCaller(entry *Entry) string {
+	return entry.Caller.File + ":" + strconv.Itoa(entry.Caller.Line)
+}
+
 func (that FieldMap) Resolve(key FieldKey) string {
 	if k, ok := that[key]; ok {
 		return k
@@ -53,6 +89,22 @@ func (that FieldMap) EncodePrefixFieldClashes(data Fields) {
 	that.encodePrefixFieldClash(data, FieldKeyLoggerError)
 }
 
+// EncodePrefixCallerClashes renames fields, that clash with the caller keys.
+func (that FieldMap) EncodePrefixCallerClashes(data Fields) {
+	that.encodePrefixFieldClash(data, FieldKeyCaller)
+	that.encodePrefixFieldClash(data, FieldKeyFunc)
+}
+
+// EncodePrefixErrorsClash renames the field, that clashes with the key of error chain.
+func (that FieldMap) EncodePrefixErrorsClash(data Fields) {
+	that.encodePrefixFieldClash(data, FieldKeyErrors)
+}
+
+// EncodePrefixStackClash renames the field, that clashes with the key of stack.
+func (that FieldMap) EncodePrefixStackClash(data Fields) {
+	that.encodePrefixFieldClash(data, FieldKeyStack)
+}
+
 func (that FieldMap) encodePrefixFieldClash(data Fields, key FieldKey) {
 	k := that.Resolve(key)
 	if l, ok := data[k]; ok {
@@ -68,6 +120,12 @@ func (that FieldMap) DecodePrefixFieldClashes(data Fields) {
 	that.decodePrefixFieldClash(data, FieldKeyLoggerError)
 }
 
+// DecodePrefixCallerClashes restores names of fields, that clash with the caller keys.
+func (that FieldMap) DecodePrefixCallerClashes(data Fields) {
+	that.decodePrefixFieldClash(data, FieldKeyCaller)
+	that.decodePrefixFieldClash(data, FieldKeyFunc)
+}
+
 func (that FieldMap) decodePrefixFieldClash(data Fields, key FieldKey) {
 	k1 := that.Resolve(key)
 	k2 := prefix + k1
@@ -0,0 +1,86 @@
+package consoleFormatter
+
+import (
+	"github.com/adverax/log"
+	"io"
+	"os"
+)
+
+type Builder struct {
+	formatter *Formatter
+	colorMode ColorMode
+	out       io.Writer
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		formatter: &Formatter{
+			timestampFormat:        log.DefaultTimestampFormat,
+			disableTimestamp:       false,
+			disableLevelTruncation: false,
+			padLevelText:           false,
+			messageWidth:           40,
+			multilineThreshold:     80,
+			fieldMap:               log.FieldMap{},
+		},
+		colorMode: ColorModeAuto,
+		out:       os.Stdout,
+	}
+}
+
+func (that *Builder) WithColorMode(mode ColorMode) *Builder {
+	that.colorMode = mode
+	return that
+}
+
+// WithOutput sets the output, that is checked to be a terminal by ColorModeAuto.
+// Default is os.Stdout.
+func (that *Builder) WithOutput(out io.Writer) *Builder {
+	that.out = out
+	return that
+}
+
+func (that *Builder) WithTimestampFormat(timestampFormat string) *Builder {
+	that.formatter.timestampFormat = timestampFormat
+	return that
+}
+
+func (that *Builder) WithDisableTimestamp(disableTimestamp bool) *Builder {
+	that.formatter.disableTimestamp = disableTimestamp
+	return that
+}
+
+// WithDisableLevelTruncation disables truncation of the level to 4 characters.
+func (that *Builder) WithDisableLevelTruncation(disableLevelTruncation bool) *Builder {
+	that.formatter.disableLevelTruncation = disableLevelTruncation
+	return that
+}
+
+// WithPadLevelText pads the level by spaces to the length of the longest level.
+func (that *Builder) WithPadLevelText(padLevelText bool) *Builder {
+	that.formatter.padLevelText = padLevelText
+	return that
+}
+
+// WithMessageWidth sets the width, the message is padded to, so fields are aligned.
+func (that *Builder) WithMessageWidth(width int) *Builder {
+	that.formatter.messageWidth = width
+	return that
+}
+
+// WithMultilineThreshold sets the length of the encoded value, since that
+// maps, slices and structs are written as indented JSON below the line.
+func (that *Builder) WithMultilineThreshold(threshold int) *Builder {
+	that.formatter.multilineThreshold = threshold
+	return that
+}
+
+func (that *Builder) WithFieldMap(fieldMap log.FieldMap) *Builder {
+	that.formatter.fieldMap = fieldMap
+	return that
+}
+
+func (that *Builder) Build() (*Formatter, error) {
+	that.formatter.colors = detectColors(that.colorMode, that.out, os.Getenv)
+	return that.formatter, nil
+}
@@ -0,0 +1,96 @@
+package consoleFormatter
+
+import (
+	"github.com/adverax/enums"
+	"github.com/adverax/log"
+	"io"
+	"os"
+)
+
+// ColorMode defines whether the output is colored.
+type ColorMode int
+
+func (that ColorMode) String() string {
+	return ColorModes.DecodeOrDefault(that, "unknown")
+}
+
+const (
+	// ColorModeAuto colors the output written to a terminal.
+	// NO_COLOR disables and FORCE_COLOR enables colors regardless of the terminal.
+	ColorModeAuto ColorMode = iota
+	// ColorModeAlways always colors the output.
+	ColorModeAlways
+	// ColorModeNever never colors the output.
+	ColorModeNever
+)
+
+var ColorModes = enums.New[ColorMode](
+	map[ColorMode]string{
+		ColorModeAuto:   "auto",
+		ColorModeAlways: "always",
+		ColorModeNever:  "never",
+	},
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorFaint   = "\x1b[2m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorGray    = "\x1b[90m"
+)
+
+func levelColor(level log.Level) string {
+	switch level {
+	case log.PanicLevel, log.FatalLevel:
+		return colorMagenta
+	case log.ErrorLevel:
+		return colorRed
+	case log.WarnLevel:
+		return colorYellow
+	case log.InfoLevel:
+		return colorCyan
+	case log.DebugLevel:
+		return colorBlue
+	default:
+		return colorGray
+	}
+}
+
+// detectColors resolves the color mode for the output.
+// FORCE_COLOR takes precedence over NO_COLOR, so colors can be forced in CI.
+func detectColors(mode ColorMode, out io.Writer, getenv func(string) string) bool {
+	switch mode {
+	case ColorModeAlways:
+		return true
+	case ColorModeNever:
+		return false
+	}
+
+	if force := getenv("FORCE_COLOR"); force != "" {
+		return force != "0" && force != "false"
+	}
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	if getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(out)
+}
+
+func isTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
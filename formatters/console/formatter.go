@@ -0,0 +1,213 @@
+package consoleFormatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/adverax/log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formatter renders entries for humans reading the console during development:
+//
+//	2024-01-02 03:04:05 INFO [http] <main.go:12> request handled          method=GET status=200
+//
+// Fields are aligned by padding of the message. Large values and multi-line strings
+// are written below the line.
+type Formatter struct {
+	colors                 bool
+	timestampFormat        string
+	disableTimestamp       bool
+	disableLevelTruncation bool
+	padLevelText           bool
+	messageWidth           int
+	multilineThreshold     int
+	fieldMap               log.FieldMap
+}
+
+// Format renders a single log entry
+func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
+	data := make(log.Fields, len(entry.Data)+len(entry.TypedFields))
+	for k, v := range entry.Fields() {
+		data[k] = v
+	}
+	logger, _ := data[log.FieldKeyLogger].(string)
+	delete(data, log.FieldKeyLogger)
+	that.fieldMap.EncodePrefixFieldClashes(data)
+	if entry.HasCaller() {
+		that.fieldMap.EncodePrefixCallerClashes(data)
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	color := levelColor(entry.Level)
+	if !that.disableTimestamp {
+		that.paint(b, colorFaint, entry.Time.Format(that.timestampFormat))
+		b.WriteByte(' ')
+	}
+	level := log.FormatLevel(entry.Level, !that.disableLevelTruncation, that.padLevelText)
+	that.paint(b, color, strings.ToUpper(level))
+	if logger != "" {
+		b.WriteString(" [")
+		b.WriteString(logger)
+		b.WriteByte(']')
+	}
+	if entry.HasCaller() {
+		b.WriteString(" <")
+		that.paint(b, colorFaint, log.FormatCaller(entry))
+		b.WriteByte('>')
+	}
+	b.WriteByte(' ')
+	b.WriteString(entry.Message)
+	if entry.LogErr != "" {
+		b.WriteString(" ")
+		that.paint(b, colorRed, "("+entry.LogErr+")")
+	}
+
+	var blocks []block
+	if len(keys) != 0 {
+		if pad := that.messageWidth - utf8.RuneCountInString(entry.Message); pad > 0 {
+			b.WriteString(strings.Repeat(" ", pad))
+		}
+		for _, key := range keys {
+			value, multiline := that.formatValue(data[key])
+			if multiline {
+				blocks = append(blocks, block{key: key, value: value})
+				continue
+			}
+			b.WriteByte(' ')
+			that.paint(b, color, key)
+			b.WriteByte('=')
+			b.WriteString(value)
+		}
+	}
+	b.WriteByte('\n')
+
+	for _, block := range blocks {
+		b.WriteString("    ")
+		that.paint(b, color, block.key)
+		b.WriteString("=")
+		b.WriteString(strings.ReplaceAll(block.value, "\n", "\n    "))
+		b.WriteByte('\n')
+	}
+	that.writeErrors(b, entry)
+	that.writeStack(b, entry.Stack)
+	return b.Bytes(), nil
+}
+
+// block is the value written below the line.
+type block struct {
+	key   string
+	value string
+}
+
+// formatValue returns the value as text and whether it is written below the line.
+func (that *Formatter) formatValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "null", false
+	case string:
+		return that.formatString(v)
+	case error:
+		return that.formatString(v.Error())
+	case time.Duration:
+		return v.String(), false
+	case time.Time:
+		return v.Format(time.RFC3339Nano), false
+	case fmt.Stringer:
+		return that.formatString(v.String())
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), false
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return that.formatString(fmt.Sprint(value))
+	}
+	if len(encoded) <= that.multilineThreshold {
+		return string(encoded), false
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, encoded, "", "  "); err != nil {
+		return string(encoded), false
+	}
+	return indented.String(), true
+}
+
+// formatString quotes strings with spaces and special characters.
+// Multi-line strings are written below the line as is.
+func (that *Formatter) formatString(s string) (string, bool) {
+	if strings.Contains(s, "\n") {
+		return "\n" + strings.TrimRight(s, "\n"), true
+	}
+	if s == "" || strings.ContainsAny(s, " =\"\t") || strconv.Quote(s) != `"`+s+`"` {
+		return strconv.Quote(s), false
+	}
+	return s, false
+}
+
+// writeErrors writes the chain of the entry error, when it wraps other errors.
+func (that *Formatter) writeErrors(b *bytes.Buffer, entry *log.Entry) {
+	err := entry.ErrorValue()
+	if err == nil {
+		return
+	}
+
+	chain := log.ErrorChain(err)
+	if len(chain) < 2 {
+		return
+	}
+
+	b.WriteString("    ")
+	that.paint(b, colorRed, "errors:")
+	b.WriteByte('\n')
+	for _, item := range chain {
+		b.WriteString("    ")
+		b.WriteString(strings.Repeat("  ", item.Depth+1))
+		b.WriteString(item.Message)
+		b.WriteByte('\n')
+	}
+}
+
+func (that *Formatter) writeStack(b *bytes.Buffer, stack log.StackTrace) {
+	if len(stack) == 0 {
+		return
+	}
+
+	b.WriteString("    ")
+	that.paint(b, colorRed, "stack:")
+	b.WriteByte('\n')
+	for _, frame := range stack {
+		fmt.Fprintf(b, "      %s\n        ", frame.Function)
+		that.paint(b, colorFaint, frame.File+":"+strconv.Itoa(frame.Line))
+		b.WriteByte('\n')
+	}
+}
+
+// paint writes the text in color, when colors are enabled.
+func (that *Formatter) paint(b *bytes.Buffer, color, text string) {
+	if !that.colors {
+		b.WriteString(text)
+		return
+	}
+
+	b.WriteString(color)
+	b.WriteString(text)
+	b.WriteString(colorReset)
+}
@@ -0,0 +1,131 @@
+package consoleFormatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFormatter(t *testing.T) {
+	type Test struct {
+		name     string
+		builder  *Builder
+		entry    *log.Entry
+		expected string
+	}
+
+	tests := []Test{
+		{
+			name:    "message",
+			builder: NewBuilder(),
+			entry: &log.Entry{
+				Level:   log.ErrorLevel,
+				Message: "Hello, World!",
+			},
+			expected: "0001-01-01 00:00:00 ERRO Hello, World!\n",
+		},
+		{
+			name:    "aligned fields",
+			builder: NewBuilder().WithDisableTimestamp(true).WithMessageWidth(16),
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello",
+				Data: log.Fields{
+					log.FieldKeyLogger: "http",
+					"status":           200,
+					"path":             "/api v1",
+					"elapsed":          time.Second,
+				},
+				Caller: &runtime.Frame{Function: "main.main", File: "main.go", Line: 12},
+			},
+			expected: "INFO [http] <main.go:12> Hello            elapsed=1s path=\"/api v1\" status=200\n",
+		},
+		{
+			name:    "padded level",
+			builder: NewBuilder().WithDisableTimestamp(true).WithDisableLevelTruncation(true).WithPadLevelText(true),
+			entry: &log.Entry{
+				Level:   log.WarnLevel,
+				Message: "Hello",
+			},
+			expected: "WARN  Hello\n",
+		},
+		{
+			name:    "multi-line",
+			builder: NewBuilder().WithDisableTimestamp(true).WithMessageWidth(0).WithMultilineThreshold(10),
+			entry: &log.Entry{
+				Level:   log.ErrorLevel,
+				Message: "Hello",
+				Data: log.Fields{
+					"payload":    log.Fields{"id": 1, "name": "bob"},
+					"short":      []int{1},
+					"text":       "line 1\nline 2\n",
+					log.ErrorKey: fmt.Errorf("outer: %w", errors.New("inner")),
+				},
+			},
+			expected: "ERRO Hello error=\"outer: inner\" short=[1]\n" +
+				"    payload={\n" +
+				"      \"id\": 1,\n" +
+				"      \"name\": \"bob\"\n" +
+				"    }\n" +
+				"    text=\n" +
+				"    line 1\n" +
+				"    line 2\n" +
+				"    errors:\n" +
+				"      outer: inner\n" +
+				"        inner\n",
+		},
+		{
+			name:    "colors",
+			builder: NewBuilder().WithDisableTimestamp(true).WithMessageWidth(0).WithColorMode(ColorModeAlways),
+			entry: &log.Entry{
+				Level:   log.WarnLevel,
+				Message: "Hello",
+				Data:    log.Fields{"key": "value"},
+			},
+			expected: "\x1b[33mWARN\x1b[0m Hello \x1b[33mkey\x1b[0m=value\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formatter, err := test.builder.WithOutput(&bytes.Buffer{}).Build()
+			require.NoError(t, err)
+			data, err := formatter.Format(test.entry)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(data))
+		})
+	}
+}
+
+func TestDetectColors(t *testing.T) {
+	type Test struct {
+		name     string
+		mode     ColorMode
+		env      map[string]string
+		expected bool
+	}
+
+	tests := []Test{
+		{name: "always", mode: ColorModeAlways, env: map[string]string{"NO_COLOR": "1"}, expected: true},
+		{name: "never", mode: ColorModeNever, env: map[string]string{"FORCE_COLOR": "1"}, expected: false},
+		{name: "not terminal", mode: ColorModeAuto, expected: false},
+		{name: "force", mode: ColorModeAuto, env: map[string]string{"FORCE_COLOR": "1", "NO_COLOR": "1"}, expected: true},
+		{name: "force disabled", mode: ColorModeAuto, env: map[string]string{"FORCE_COLOR": "0"}, expected: false},
+		{name: "no color", mode: ColorModeAuto, env: map[string]string{"NO_COLOR": "1"}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			getenv := func(key string) string {
+				return test.env[key]
+			}
+			assert.Equal(t, test.expected, detectColors(test.mode, &bytes.Buffer{}, getenv))
+		})
+	}
+}
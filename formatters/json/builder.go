@@ -1,6 +1,9 @@
 package jsonFormatter
 
-import "github.com/adverax/log"
+import (
+	"errors"
+	"github.com/adverax/log"
+)
 
 type Builder struct {
 	formatter *Formatter
@@ -13,17 +16,32 @@ func NewBuilder() *Builder {
 			disableTimestamp:  false,
 			disableHTMLEscape: false,
 			dataKey:           log.FieldKeyData,
+			layout:            LayoutNested,
+			expandDottedKeys:  false,
 			fieldMap:          nil,
 			prettyPrint:       false,
 		},
 	}
 }
 
+// WithDataKey sets the key of the object with fields in LayoutNested.
 func (that *Builder) WithDataKey(key string) *Builder {
 	that.formatter.dataKey = key
 	return that
 }
 
+func (that *Builder) WithLayout(layout Layout) *Builder {
+	that.formatter.layout = layout
+	return that
+}
+
+// WithExpandDottedKeys enables expanding of dotted keys into nested objects,
+// so "http.method" becomes {"http":{"method":...}}.
+func (that *Builder) WithExpandDottedKeys(expand bool) *Builder {
+	that.formatter.expandDottedKeys = expand
+	return that
+}
+
 func (that *Builder) WithFieldMap(fieldMap log.FieldMap) *Builder {
 	that.formatter.fieldMap = fieldMap
 	return that
@@ -50,5 +68,20 @@ func (that *Builder) WithDisableHTMLEscape(disableHTMLEss bool) *Builder {
 }
 
 func (that *Builder) Build() (*Formatter, error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+
 	return that.formatter, nil
 }
+
+func (that *Builder) checkRequiredFields() error {
+	if that.formatter.layout == LayoutNested && that.formatter.dataKey == "" {
+		return ErrRequiredFieldDataKey
+	}
+	return nil
+}
+
+var (
+	ErrRequiredFieldDataKey = errors.New("data key is required")
+)
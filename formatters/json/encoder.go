@@ -0,0 +1,360 @@
+package jsonFormatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/adverax/log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const hex = "0123456789abcdef"
+
+// encoder writes JSON directly into the buffer. It implements log.ObjectEncoder,
+// so typed fields and objects are encoded without reflection.
+type encoder struct {
+	buf        *bytes.Buffer
+	escapeHTML bool
+	keys       []string
+}
+
+var encoders = sync.Pool{
+	New: func() interface{} {
+		return &encoder{}
+	},
+}
+
+func getEncoder(buf *bytes.Buffer, escapeHTML bool) *encoder {
+	enc := encoders.Get().(*encoder)
+	enc.buf = buf
+	enc.escapeHTML = escapeHTML
+	return enc
+}
+
+func putEncoder(enc *encoder) {
+	enc.buf = nil
+	clear(enc.keys)
+	enc.keys = enc.keys[:0]
+	encoders.Put(enc)
+}
+
+func (that *encoder) AddString(key, value string) {
+	that.addKey(key)
+	that.appendString(value)
+}
+
+func (that *encoder) AddInt64(key string, value int64) {
+	that.addKey(key)
+	that.appendInt64(value)
+}
+
+func (that *encoder) AddFloat64(key string, value float64) {
+	that.addKey(key)
+	that.appendFloat(value, 64)
+}
+
+func (that *encoder) AddBool(key string, value bool) {
+	that.addKey(key)
+	that.buf.Write(strconv.AppendBool(that.buf.AvailableBuffer(), value))
+}
+
+// AddDuration encodes the duration as nanoseconds, as encoding/json does.
+func (that *encoder) AddDuration(key string, value time.Duration) {
+	that.AddInt64(key, int64(value))
+}
+
+func (that *encoder) AddTime(key string, value time.Time) {
+	that.addKey(key)
+	that.appendTime(value, time.RFC3339Nano)
+}
+
+func (that *encoder) AddAny(key string, value interface{}) error {
+	that.addKey(key)
+	return that.appendAny(value)
+}
+
+func (that *encoder) AddObject(key string, value log.ObjectMarshaler) error {
+	that.addKey(key)
+	that.buf.WriteByte('{')
+	err := value.MarshalLogObject(that)
+	that.buf.WriteByte('}')
+	return err
+}
+
+func (that *encoder) addKey(key string) {
+	that.separate()
+	that.appendString(key)
+	that.buf.WriteByte(':')
+}
+
+// separate writes the comma between elements, unless the element is first.
+func (that *encoder) separate() {
+	n := that.buf.Len()
+	if n == 0 {
+		return
+	}
+
+	switch that.buf.Bytes()[n-1] {
+	case '{', '[', ':', ',':
+	default:
+		that.buf.WriteByte(',')
+	}
+}
+
+func (that *encoder) appendInt64(value int64) {
+	that.buf.Write(strconv.AppendInt(that.buf.AvailableBuffer(), value, 10))
+}
+
+func (that *encoder) appendUint64(value uint64) {
+	that.buf.Write(strconv.AppendUint(that.buf.AvailableBuffer(), value, 10))
+}
+
+// appendFloat formats the number of bitSize as encoding/json does.
+// NaN and infinities are not representable in JSON, so they are written as strings.
+func (that *encoder) appendFloat(value float64, bitSize int) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		that.appendString(strconv.FormatFloat(value, 'g', -1, bitSize))
+		return
+	}
+
+	format := byte('f')
+	if abs := math.Abs(value); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) || bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	b := strconv.AppendFloat(that.buf.AvailableBuffer(), value, format, -1, bitSize)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	that.buf.Write(b)
+}
+
+// appendTime writes the formatted time. It is escaped only when the layout requires escaping.
+func (that *encoder) appendTime(value time.Time, layout string) {
+	that.buf.WriteByte('"')
+	b := value.AppendFormat(that.buf.AvailableBuffer(), layout)
+	for _, c := range b {
+		if c < 0x20 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' || c >= utf8.RuneSelf {
+			s := string(b)
+			that.buf.Truncate(that.buf.Len() - 1)
+			that.appendString(s)
+			return
+		}
+	}
+	that.buf.Write(b)
+	that.buf.WriteByte('"')
+}
+
+// appendAny encodes common types by fast paths and values of unknown types by encoding/json.
+func (that *encoder) appendAny(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		that.buf.WriteString("null")
+	case string:
+		that.appendString(v)
+	case bool:
+		that.buf.Write(strconv.AppendBool(that.buf.AvailableBuffer(), v))
+	case int:
+		that.appendInt64(int64(v))
+	case int8:
+		that.appendInt64(int64(v))
+	case int16:
+		that.appendInt64(int64(v))
+	case int32:
+		that.appendInt64(int64(v))
+	case int64:
+		that.appendInt64(v)
+	case uint:
+		that.appendUint64(uint64(v))
+	case uint8:
+		that.appendUint64(uint64(v))
+	case uint16:
+		that.appendUint64(uint64(v))
+	case uint32:
+		that.appendUint64(uint64(v))
+	case uint64:
+		that.appendUint64(v)
+	case float32:
+		that.appendFloat(float64(v), 32)
+	case float64:
+		that.appendFloat(v, 64)
+	case time.Duration:
+		that.appendInt64(int64(v))
+	case time.Time:
+		that.appendTime(v, time.RFC3339Nano)
+	case error:
+		// Otherwise errors are encoded as empty objects by encoding/json.
+		that.appendString(v.Error())
+	case log.ObjectMarshaler:
+		that.buf.WriteByte('{')
+		err := v.MarshalLogObject(that)
+		that.buf.WriteByte('}')
+		return err
+	case log.Fields:
+		return that.appendFields(v, nil)
+	case map[string]interface{}:
+		return that.appendFields(v, nil)
+	case []interface{}:
+		that.buf.WriteByte('[')
+		for _, item := range v {
+			that.separate()
+			if err := that.appendAny(item); err != nil {
+				return err
+			}
+		}
+		that.buf.WriteByte(']')
+	case []string:
+		that.buf.WriteByte('[')
+		for _, item := range v {
+			that.separate()
+			that.appendString(item)
+		}
+		that.buf.WriteByte(']')
+	default:
+		return that.appendReflected(value)
+	}
+	return nil
+}
+
+// appendReflected encodes values of unknown types by encoding/json.
+func (that *encoder) appendReflected(value interface{}) error {
+	enc := json.NewEncoder(that.buf)
+	enc.SetEscapeHTML(that.escapeHTML)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	// Encode terminates the value by newline.
+	that.buf.Truncate(that.buf.Len() - 1)
+	return nil
+}
+
+// appendString writes the quoted string, escaping it as encoding/json does.
+func (that *encoder) appendString(s string) {
+	b := that.buf
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && (!that.escapeHTML || c != '<' && c != '>' && c != '&') {
+				i++
+				continue
+			}
+			b.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			default:
+				b.WriteString(`\u00`)
+				b.WriteByte(hex[c>>4])
+				b.WriteByte(hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(s[start:i])
+			b.WriteRune(utf8.RuneError)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are line separators in JavaScript.
+		if r == '\u2028' || r == '\u2029' {
+			b.WriteString(s[start:i])
+			b.WriteString(`\u202`)
+			b.WriteByte(hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b.WriteString(s[start:])
+	b.WriteByte('"')
+}
+
+// appendFields writes fields merged with typed fields as an object with keys in sorted order.
+func (that *encoder) appendFields(fields map[string]interface{}, typed []log.Field) error {
+	that.buf.WriteByte('{')
+	if err := that.appendMembers(fields, typed); err != nil {
+		return err
+	}
+	that.buf.WriteByte('}')
+	return nil
+}
+
+// appendMembers writes members in sorted order.
+func (that *encoder) appendMembers(fields map[string]interface{}, typed []log.Field) error {
+	start := len(that.keys)
+	defer that.releaseKeys(start)
+
+	for _, k := range that.collectKeys(fields, typed) {
+		if err := that.addMember(k, fields, typed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectKeys returns sorted keys of fields merged with typed fields.
+// Keys are collected on top of the keys of enclosing objects, so the slice is reused
+// until releaseKeys.
+func (that *encoder) collectKeys(fields map[string]interface{}, typed []log.Field) []string {
+	start := len(that.keys)
+	for k := range fields {
+		if _, ok := lastField(typed, k); !ok {
+			that.keys = append(that.keys, k)
+		}
+	}
+	for i, field := range typed {
+		if _, ok := lastField(typed[i+1:], field.Key); !ok {
+			that.keys = append(that.keys, field.Key)
+		}
+	}
+	keys := that.keys[start:]
+	sort.Strings(keys)
+	return keys
+}
+
+func (that *encoder) releaseKeys(start int) {
+	clear(that.keys[start:])
+	that.keys = that.keys[:start]
+}
+
+// addMember writes the member with key. The last typed field with the key
+// takes precedence over others.
+func (that *encoder) addMember(key string, fields map[string]interface{}, typed []log.Field) error {
+	if field, ok := lastField(typed, key); ok {
+		return field.AddTo(that)
+	}
+	return that.AddAny(key, fields[key])
+}
+
+func lastField(fields []log.Field, key string) (log.Field, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == key {
+			return fields[i], true
+		}
+	}
+	return log.Field{}, false
+}
@@ -12,48 +12,242 @@ type Formatter struct {
 	disableTimestamp  bool
 	disableHTMLEscape bool
 	dataKey           string
+	layout            Layout
+	expandDottedKeys  bool
 	fieldMap          log.FieldMap
 	prettyPrint       bool
 }
 
-// Format renders a single log entry
+// Format renders a single log entry.
+// Members are written by the streaming encoder directly into the buffer of the entry
+// in the order of keys, so the output is the same as of encoding/json.
 func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
-	data := entry.Data.Expand()
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
 
-	newData := make(log.Fields, 4)
-	if len(data) > 0 {
-		newData[log.FieldKeyData] = data
+	out := b
+	if that.prettyPrint {
+		out = &bytes.Buffer{}
 	}
-	data = newData
 
-	that.fieldMap.EncodePrefixFieldClashes(data)
+	var chain []log.ErrorChainItem
+	if err := entry.ErrorValue(); err != nil {
+		if chain = log.ErrorChain(err); len(chain) < 2 {
+			chain = nil
+		}
+	}
 
-	timestampFormat := that.timestampFormat
+	// Encoded bound fields are reused, unless there are typed fields to merge.
+	data, typed := entry.Data, entry.TypedFields
+	cached := entry.Bound != nil && len(typed) == 0 && that.layout == LayoutNested
+	if !cached && that.expandDottedKeys && hasDottedKeys(data, typed) {
+		data, typed = expandDottedKeys(mergeFields(data, typed)), nil
+	}
+	if that.layout == LayoutFlat && that.hasClashes(entry, chain, data, typed) {
+		data, typed = mergeFields(data, typed), nil
+		that.encodePrefixClashes(entry, chain, data)
+	}
 
+	var ms members
+	if that.layout == LayoutNested && (len(data) != 0 || len(typed) != 0) {
+		ms.add(that.dataMemberKey(entry, chain), memberData)
+	}
 	if entry.LogErr != "" {
-		data[that.fieldMap.Resolve(log.FieldKeyLoggerError)] = entry.LogErr
+		ms.add(that.fieldMap.Resolve(log.FieldKeyLoggerError), memberLoggerError)
 	}
 	if !that.disableTimestamp {
-		data[that.fieldMap.Resolve(log.FieldKeyTime)] = entry.Time.Format(timestampFormat)
+		ms.add(that.fieldMap.Resolve(log.FieldKeyTime), memberTime)
+	}
+	ms.add(that.fieldMap.Resolve(log.FieldKeyMsg), memberMsg)
+	ms.add(that.fieldMap.Resolve(log.FieldKeyLevel), memberLevel)
+	if entry.HasCaller() {
+		ms.add(that.fieldMap.Resolve(log.FieldKeyFunc), memberFunc)
+		ms.add(that.fieldMap.Resolve(log.FieldKeyCaller), memberCaller)
+	}
+	if chain != nil {
+		ms.add(that.fieldMap.Resolve(log.FieldKeyErrors), memberErrors)
+	}
+	if entry.Stack != nil {
+		ms.add(that.fieldMap.Resolve(log.FieldKeyStack), memberStack)
 	}
-	data[that.fieldMap.Resolve(log.FieldKeyMsg)] = entry.Message
-	data[that.fieldMap.Resolve(log.FieldKeyLevel)] = entry.Level.String()
+	ms.sort()
 
-	var b *bytes.Buffer
-	if entry.Buffer != nil {
-		b = entry.Buffer
-	} else {
-		b = &bytes.Buffer{}
+	enc := getEncoder(out, !that.disableHTMLEscape)
+	defer putEncoder(enc)
+
+	var keys []string
+	if that.layout == LayoutFlat {
+		keys = enc.collectKeys(data, typed)
+	}
+
+	out.WriteByte('{')
+	// Members of the entry and fields are merged in the order of keys.
+	for i, j := 0, 0; i < ms.count || j < len(keys); {
+		if i == ms.count || j < len(keys) && keys[j] < ms.items[i].key {
+			if err := enc.addMember(keys[j], data, typed); err != nil {
+				return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+			}
+			j++
+			continue
+		}
+
+		m := ms.items[i]
+		i++
+		enc.addKey(m.key)
+		switch m.kind {
+		case memberData:
+			if err := that.writeData(enc, entry, data, typed, cached); err != nil {
+				return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+			}
+		case memberLoggerError:
+			enc.appendString(entry.LogErr)
+		case memberTime:
+			enc.appendTime(entry.Time, that.timestampFormat)
+		case memberMsg:
+			enc.appendString(entry.Message)
+		case memberLevel:
+			enc.appendString(entry.Level.String())
+		case memberFunc:
+			enc.appendString(entry.Caller.Function)
+		case memberCaller:
+			enc.appendString(log.FormatCaller(entry))
+		case memberErrors:
+			that.writeErrors(enc, chain)
+		case memberStack:
+			that.writeStack(enc, entry.Stack)
+		}
 	}
+	out.WriteByte('}')
 
-	encoder := json.NewEncoder(b)
-	encoder.SetEscapeHTML(!that.disableHTMLEscape)
 	if that.prettyPrint {
-		encoder.SetIndent("", "  ")
+		if err := json.Indent(b, out.Bytes(), "", "  "); err != nil {
+			return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+		}
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+// memberKeys are keys of members of the entry, that may clash with fields.
+var memberKeys = [...]log.FieldKey{
+	log.FieldKeyTime, log.FieldKeyMsg, log.FieldKeyLevel, log.FieldKeyLoggerError,
+	log.FieldKeyCaller, log.FieldKeyFunc, log.FieldKeyErrors, log.FieldKeyStack,
+}
+
+// hasMember reports whether the entry has the member with the key.
+// Time, message, level and logger error are treated as always present.
+func hasMember(entry *log.Entry, chain []log.ErrorChainItem, key log.FieldKey) bool {
+	switch key {
+	case log.FieldKeyCaller, log.FieldKeyFunc:
+		return entry.HasCaller()
+	case log.FieldKeyErrors:
+		return chain != nil
+	case log.FieldKeyStack:
+		return entry.Stack != nil
+	}
+	return true
+}
+
+// dataMemberKey returns the data key, renamed as EncodePrefixFieldClashes does,
+// when it clashes with other members of the entry.
+func (that *Formatter) dataMemberKey(entry *log.Entry, chain []log.ErrorChainItem) string {
+	key := that.dataKey
+	for _, k := range memberKeys {
+		if hasMember(entry, chain, k) && key == that.fieldMap.Resolve(k) {
+			key = "fields." + key
+		}
 	}
-	if err := encoder.Encode(data); err != nil {
-		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+	return key
+}
+
+// writeData writes data merged with typed fields. Typed fields take precedence.
+func (that *Formatter) writeData(enc *encoder, entry *log.Entry, data log.Fields, typed []log.Field, cached bool) error {
+	if cached {
+		encoded, err := entry.Bound.Encoded(that, that.encodeFields)
+		if err != nil {
+			return err
+		}
+		enc.buf.Write(encoded)
+		return nil
 	}
 
+	return enc.appendFields(data, typed)
+}
+
+// hasClashes reports whether some of fields clash with members of the entry in the flat layout.
+func (that *Formatter) hasClashes(entry *log.Entry, chain []log.ErrorChainItem, data log.Fields, typed []log.Field) bool {
+	for _, key := range memberKeys {
+		if !hasMember(entry, chain, key) {
+			continue
+		}
+
+		k := that.fieldMap.Resolve(key)
+		if _, ok := data[k]; ok {
+			return true
+		}
+		if _, ok := lastField(typed, k); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (that *Formatter) encodePrefixClashes(entry *log.Entry, chain []log.ErrorChainItem, data log.Fields) {
+	that.fieldMap.EncodePrefixFieldClashes(data)
+	if entry.HasCaller() {
+		that.fieldMap.EncodePrefixCallerClashes(data)
+	}
+	if chain != nil {
+		that.fieldMap.EncodePrefixErrorsClash(data)
+	}
+	if entry.Stack != nil {
+		that.fieldMap.EncodePrefixStackClash(data)
+	}
+}
+
+func (that *Formatter) writeErrors(enc *encoder, chain []log.ErrorChainItem) {
+	enc.buf.WriteByte('[')
+	for _, item := range chain {
+		enc.separate()
+		enc.buf.WriteByte('{')
+		enc.AddInt64("depth", int64(item.Depth))
+		enc.AddString("type", item.Type)
+		enc.AddString("msg", item.Message)
+		enc.buf.WriteByte('}')
+	}
+	enc.buf.WriteByte(']')
+}
+
+func (that *Formatter) writeStack(enc *encoder, stack log.StackTrace) {
+	enc.buf.WriteByte('[')
+	for _, frame := range stack {
+		enc.separate()
+		enc.buf.WriteByte('{')
+		enc.AddString("func", frame.Function)
+		enc.AddString("file", frame.File)
+		enc.AddInt64("line", int64(frame.Line))
+		enc.buf.WriteByte('}')
+	}
+	enc.buf.WriteByte(']')
+}
+
+// encodeFields encodes fields bound to the logger once, so they are reused by entries.
+func (that *Formatter) encodeFields(fields log.Fields) ([]byte, error) {
+	if that.expandDottedKeys && hasDottedKeys(fields, nil) {
+		fields = expandDottedKeys(fields)
+	}
+
+	var b bytes.Buffer
+	enc := getEncoder(&b, !that.disableHTMLEscape)
+	defer putEncoder(enc)
+
+	if err := enc.appendFields(fields, nil); err != nil {
+		return nil, err
+	}
 	return b.Bytes(), nil
 }
@@ -1,8 +1,15 @@
 package jsonFormatter
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"github.com/adverax/log"
+	fileExporter "github.com/adverax/log/exporters/file"
 	"github.com/stretchr/testify/require"
+	"io"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -35,16 +42,357 @@ func TestFormatter(t *testing.T) {
 			},
 			expected: "{\"data\":{\"key\":\"value\"},\"level\":\"error\",\"msg\":\"Hello, World2!\",\"time\":\"0001-01-01 00:00:00\"}\n",
 		},
+		{
+			name: "caller",
+			entry: &log.Entry{
+				Time:    time.Time{},
+				Level:   log.InfoLevel,
+				Message: "Hello, World3!",
+				Data:    log.Fields{},
+				Caller:  &runtime.Frame{Function: "main.main", File: "/app/main.go", Line: 12},
+			},
+			expected: "{\"caller\":\"/app/main.go:12\",\"func\":\"main.main\",\"level\":\"info\",\"msg\":\"Hello, World3!\",\"time\":\"0001-01-01 00:00:00\"}\n",
+		},
+	}
+
+	formatter, err := NewBuilder().Build()
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := formatter.Format(test.entry)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(data))
+		})
+	}
+}
+
+func TestFormatterBoundFields(t *testing.T) {
+	formatter, err := NewBuilder().WithDisableTimestamp(true).Build()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	logger, err := log.NewBuilder().
+		WithExporter(fileExporter.New(formatter, &out)).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fields := log.Fields{"key": "<value>", "count": 2}
+	logger.WithFields(fields).Info(ctx, "Hello, World!")
+	expected := out.String()
+
+	bound := logger.With(fields)
+	for i := 0; i < 2; i++ {
+		out.Reset()
+		bound.Info(ctx, "Hello, World!")
+		require.Equal(t, expected, out.String())
+	}
+}
+
+func BenchmarkFormatterWithFields(b *testing.B) {
+	formatter, _ := NewBuilder().Build()
+	logger, _ := log.NewBuilder().
+		WithExporter(fileExporter.New(formatter, io.Discard)).
+		Build()
+	ctx := context.Background()
+	fields := log.Fields{"request_id": "r1", "user_id": 42, "path": "/api"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.WithFields(fields).Info(ctx, "Hello, World!")
+	}
+}
+
+func BenchmarkFormatterWith(b *testing.B) {
+	formatter, _ := NewBuilder().Build()
+	logger, _ := log.NewBuilder().
+		WithExporter(fileExporter.New(formatter, io.Discard)).
+		Build()
+	ctx := context.Background()
+	bound := logger.With(log.Fields{"request_id": "r1", "user_id": 42, "path": "/api"})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bound.Info(ctx, "Hello, World!")
+	}
+}
+
+type testUser struct {
+	name string
+	age  int64
+}
+
+func (that testUser) MarshalLogObject(encoder log.ObjectEncoder) error {
+	encoder.AddString("name", that.name)
+	encoder.AddInt64("age", that.age)
+	return nil
+}
+
+func TestFormatterTypedFields(t *testing.T) {
+	type Test struct {
+		name     string
+		entry    *log.Entry
+		expected string
+	}
+
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	tests := []Test{
+		{
+			name: "scalars",
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+				TypedFields: []log.Field{
+					log.String("path", "/api?a=<b>"),
+					log.Int64("count", 2),
+					log.Float64("ratio", 0.5),
+					log.Bool("ok", true),
+					log.Duration("elapsed", time.Second),
+					log.Time("at", tm),
+					log.Err(errors.New("failure")),
+				},
+			},
+			expected: `{"data":{"at":"2024-01-02T03:04:05.000000006Z","count":2,"elapsed":1000000000,"error":"failure","ok":true,"path":"/api?a=\u003cb\u003e","ratio":0.5},"level":"info","msg":"Hello, World!","time":"0001-01-01 00:00:00"}` + "\n",
+		},
+		{
+			name: "object and any",
+			entry: &log.Entry{
+				Level:   log.WarnLevel,
+				Message: "Hello, World!",
+				TypedFields: []log.Field{
+					log.Object("user", testUser{name: "bob", age: 42}),
+					log.Any("tags", []string{"a", "b"}),
+				},
+			},
+			expected: `{"data":{"tags":["a","b"],"user":{"name":"bob","age":42}},"level":"warn","msg":"Hello, World!","time":"0001-01-01 00:00:00"}` + "\n",
+		},
+		{
+			name: "merged with data",
+			entry: &log.Entry{
+				Level:   log.ErrorLevel,
+				Message: "Hello, World!",
+				Data:    log.Fields{"b": 1, "a": "x", "key": "old"},
+				TypedFields: []log.Field{
+					log.String("key", "new"),
+				},
+			},
+			expected: `{"data":{"a":"x","b":1,"key":"new"},"level":"error","msg":"Hello, World!","time":"0001-01-01 00:00:00"}` + "\n",
+		},
+	}
+
+	formatter, err := NewBuilder().Build()
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := formatter.Format(test.entry)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(data))
+		})
+	}
+}
+
+func TestFormatterTypedFieldsMatchFields(t *testing.T) {
+	formatter, err := NewBuilder().WithPrettyPrint(true).Build()
+	require.NoError(t, err)
+
+	typed := &log.Entry{
+		Level:       log.ErrorLevel,
+		Message:     "Hello, World!",
+		LogErr:      "can not add field \"fn\"",
+		Caller:      &runtime.Frame{Function: "main.main", File: "/app/main.go", Line: 12},
+		TypedFields: []log.Field{log.Err(fmt.Errorf("wrapped: %w", errors.New("failure")))},
+	}
+	expected, err := formatter.Format(typed)
+	require.NoError(t, err)
+
+	fields := &log.Entry{
+		Level:   typed.Level,
+		Message: typed.Message,
+		LogErr:  typed.LogErr,
+		Caller:  typed.Caller,
+		Data:    typed.Fields(),
+	}
+	actual, err := formatter.Format(fields)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+func TestFormatterTypedFieldsAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
 	}
 
 	formatter, err := NewBuilder().Build()
 	require.NoError(t, err)
+	logger, err := log.NewBuilder().
+		WithExporter(fileExporter.New(formatter, io.Discard)).
+		Build()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.InfoFields(ctx, "Hello, World!",
+			log.String("request_id", "r1"),
+			log.Int64("user_id", 42),
+			log.Duration("elapsed", time.Millisecond),
+		)
+	})
+	require.Zero(t, allocs)
+}
+
+func BenchmarkFormatterTypedFields(b *testing.B) {
+	formatter, _ := NewBuilder().Build()
+	logger, _ := log.NewBuilder().
+		WithExporter(fileExporter.New(formatter, io.Discard)).
+		Build()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.InfoFields(ctx, "Hello, World!",
+			log.String("request_id", "r1"),
+			log.Int64("user_id", 42),
+			log.String("path", "/api"),
+		)
+	}
+}
+
+func TestFormatterLayout(t *testing.T) {
+	type Test struct {
+		name     string
+		builder  *Builder
+		entry    *log.Entry
+		expected string
+	}
+
+	tests := []Test{
+		{
+			name:    "nested under data key",
+			builder: NewBuilder().WithDataKey("fields"),
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+				Data:    log.Fields{"key": "value"},
+			},
+			expected: `{"fields":{"key":"value"},"level":"info","msg":"Hello, World!"}` + "\n",
+		},
+		{
+			name:    "nested under data key clashing with caller",
+			builder: NewBuilder().WithDataKey("caller"),
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+				Data:    log.Fields{"key": "value"},
+				Caller:  &runtime.Frame{Function: "main.main", File: "/app/main.go", Line: 12},
+			},
+			expected: `{"caller":"/app/main.go:12","fields.caller":{"key":"value"},"func":"main.main","level":"info","msg":"Hello, World!"}` + "\n",
+		},
+		{
+			name:    "nested under data key clashing with stack",
+			builder: NewBuilder().WithDataKey("stack"),
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+				Data:    log.Fields{"key": "value"},
+				Stack:   log.StackTrace{{Function: "main.main", File: "/app/main.go", Line: 12}},
+			},
+			expected: `{"fields.stack":{"key":"value"},"level":"info","msg":"Hello, World!","stack":[{"func":"main.main","file":"/app/main.go","line":12}]}` + "\n",
+		},
+		{
+			name:    "flat",
+			builder: NewBuilder().WithLayout(LayoutFlat),
+			entry: &log.Entry{
+				Level:       log.InfoLevel,
+				Message:     "Hello, World!",
+				Data:        log.Fields{"key": "value", "zoo": 1},
+				TypedFields: []log.Field{log.Int64("count", 2)},
+			},
+			expected: `{"count":2,"key":"value","level":"info","msg":"Hello, World!","zoo":1}` + "\n",
+		},
+		{
+			name:    "flat with clashes",
+			builder: NewBuilder().WithLayout(LayoutFlat),
+			entry: &log.Entry{
+				Level:       log.InfoLevel,
+				Message:     "Hello, World!",
+				Data:        log.Fields{"msg": "field", "caller": "field"},
+				TypedFields: []log.Field{log.String("level", "field")},
+				Caller:      &runtime.Frame{Function: "main.main", File: "/app/main.go", Line: 12},
+			},
+			expected: `{"caller":"/app/main.go:12","fields.caller":"field","fields.level":"field","fields.msg":"field","func":"main.main","level":"info","msg":"Hello, World!"}` + "\n",
+		},
+		{
+			name:    "flat with stack clash only",
+			builder: NewBuilder().WithLayout(LayoutFlat),
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+				Data:    log.Fields{"errors": 2, "stack": "field"},
+				Stack:   log.StackTrace{{Function: "main.main", File: "/app/main.go", Line: 12}},
+			},
+			expected: `{"errors":2,"fields.stack":"field","level":"info","msg":"Hello, World!","stack":[{"func":"main.main","file":"/app/main.go","line":12}]}` + "\n",
+		},
+		{
+			name:    "nested with dotted keys",
+			builder: NewBuilder().WithExpandDottedKeys(true),
+			entry: &log.Entry{
+				Level:       log.InfoLevel,
+				Message:     "Hello, World!",
+				Data:        log.Fields{"http.method": "GET", "http": log.Fields{"status": 200}, "a.": 1, "x": 1},
+				TypedFields: []log.Field{log.String("http.url.path", "/api"), log.Int("x.y", 2)},
+			},
+			expected: `{"data":{"a.":1,"http":{"method":"GET","status":200,"url":{"path":"/api"}},"x":1,"x.y":2},"level":"info","msg":"Hello, World!"}` + "\n",
+		},
+		{
+			name:    "flat with dotted keys",
+			builder: NewBuilder().WithLayout(LayoutFlat).WithExpandDottedKeys(true),
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+				Data:    log.Fields{"http.method": "GET", "msg.text": "field"},
+			},
+			expected: `{"fields.msg":{"text":"field"},"http":{"method":"GET"},"level":"info","msg":"Hello, World!"}` + "\n",
+		},
+	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			formatter, err := test.builder.WithDisableTimestamp(true).Build()
+			require.NoError(t, err)
 			data, err := formatter.Format(test.entry)
 			require.NoError(t, err)
 			require.Equal(t, test.expected, string(data))
 		})
 	}
 }
+
+func TestFormatterLayoutBoundFields(t *testing.T) {
+	formatter, err := NewBuilder().
+		WithDisableTimestamp(true).
+		WithExpandDottedKeys(true).
+		Build()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	logger, err := log.NewBuilder().
+		WithExporter(fileExporter.New(formatter, &out)).
+		Build()
+	require.NoError(t, err)
+
+	bound := logger.With(log.Fields{"http.method": "GET"})
+	bound.Info(context.Background(), "Hello, World!")
+	require.Equal(t, `{"data":{"http":{"method":"GET"}},"level":"info","msg":"Hello, World!"}`+"\n", out.String())
+}
+
+func TestBuilderRequiresDataKey(t *testing.T) {
+	_, err := NewBuilder().WithDataKey("").Build()
+	require.ErrorIs(t, err, ErrRequiredFieldDataKey)
+
+	_, err = NewBuilder().WithDataKey("").WithLayout(LayoutFlat).Build()
+	require.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+package jsonFormatter
+
+import (
+	"github.com/adverax/enums"
+	"github.com/adverax/log"
+	"sort"
+	"strings"
+)
+
+// Layout defines where fields of the entry are placed.
+type Layout int
+
+func (that Layout) String() string {
+	return Layouts.DecodeOrDefault(that, "unknown")
+}
+
+const (
+	// LayoutNested places fields into the object under the data key.
+	LayoutNested Layout = iota
+	// LayoutFlat places fields at the top level, as Elasticsearch and Loki pipelines expect.
+	// Fields clashing with keys of the entry are prefixed by "fields.".
+	LayoutFlat
+)
+
+var Layouts = enums.New[Layout](
+	map[Layout]string{
+		LayoutNested: "nested",
+		LayoutFlat:   "flat",
+	},
+)
+
+// hasDottedKeys reports whether some of keys contain a dot.
+func hasDottedKeys(data log.Fields, typed []log.Field) bool {
+	for k := range data {
+		if strings.IndexByte(k, '.') >= 0 {
+			return true
+		}
+	}
+	for _, field := range typed {
+		if strings.IndexByte(field.Key, '.') >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// expandDottedKeys returns a copy of fields, where dotted keys are expanded into nested objects,
+// so "http.method" becomes {"http":{"method":...}}. Nested objects are expanded too.
+// A dotted key is kept as is, if its path goes through a value, that is not an object.
+func expandDottedKeys(fields map[string]interface{}) log.Fields {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	// Plain keys precede dotted keys with the same prefix, so dotted keys are merged into them.
+	sort.Strings(keys)
+
+	result := make(log.Fields, len(fields))
+	for _, k := range keys {
+		value := expandValue(fields[k])
+		path := strings.Split(k, ".")
+		if len(path) == 1 || !insertPath(result, path, value) {
+			result[k] = value
+		}
+	}
+	return result
+}
+
+func expandValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case log.Fields:
+		return expandDottedKeys(v)
+	case map[string]interface{}:
+		return expandDottedKeys(v)
+	default:
+		return value
+	}
+}
+
+// insertPath puts the value into the nested object by the path of keys.
+// Objects on the path are created by expandDottedKeys, so they are modified in place.
+func insertPath(dst log.Fields, path []string, value interface{}) bool {
+	for _, key := range path {
+		if key == "" {
+			return false
+		}
+	}
+
+	for _, key := range path[:len(path)-1] {
+		switch next := dst[key].(type) {
+		case nil:
+			if _, ok := dst[key]; ok {
+				return false
+			}
+			child := make(log.Fields)
+			dst[key] = child
+			dst = child
+		case log.Fields:
+			dst = next
+		default:
+			return false
+		}
+	}
+
+	dst[path[len(path)-1]] = value
+	return true
+}
+
+// mergeFields returns a copy of data merged with typed fields.
+func mergeFields(data log.Fields, typed []log.Field) log.Fields {
+	result := make(log.Fields, len(data)+len(typed))
+	for k, v := range data {
+		result[k] = v
+	}
+	for _, field := range typed {
+		result[field.Key] = field.Value()
+	}
+	return result
+}
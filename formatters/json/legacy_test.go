@@ -0,0 +1,227 @@
+package jsonFormatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// legacyFormatter is the former implementation of Formatter, that builds maps
+// and encodes them by encoding/json. It is kept to compare the output and performance.
+type legacyFormatter struct {
+	*Formatter
+}
+
+type legacyErrorItem struct {
+	Depth   int    `json:"depth"`
+	Type    string `json:"type"`
+	Message string `json:"msg"`
+}
+
+type legacyFrameItem struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (that *legacyFormatter) Format(entry *log.Entry) ([]byte, error) {
+	data := make(log.Fields, 4)
+	if fields := entry.Fields(); len(fields) > 0 {
+		data[log.FieldKeyData] = fields.Expand()
+	}
+
+	that.fieldMap.EncodePrefixFieldClashes(data)
+
+	if entry.LogErr != "" {
+		data[that.fieldMap.Resolve(log.FieldKeyLoggerError)] = entry.LogErr
+	}
+	if !that.disableTimestamp {
+		data[that.fieldMap.Resolve(log.FieldKeyTime)] = entry.Time.Format(that.timestampFormat)
+	}
+	data[that.fieldMap.Resolve(log.FieldKeyMsg)] = entry.Message
+	data[that.fieldMap.Resolve(log.FieldKeyLevel)] = entry.Level.String()
+	if entry.HasCaller() {
+		data[that.fieldMap.Resolve(log.FieldKeyFunc)] = entry.Caller.Function
+		data[that.fieldMap.Resolve(log.FieldKeyCaller)] = log.FormatCaller(entry)
+	}
+	if err := entry.ErrorValue(); err != nil {
+		if chain := log.ErrorChain(err); len(chain) > 1 {
+			items := make([]legacyErrorItem, 0, len(chain))
+			for _, item := range chain {
+				items = append(items, legacyErrorItem{Depth: item.Depth, Type: item.Type, Message: item.Message})
+			}
+			data[that.fieldMap.Resolve(log.FieldKeyErrors)] = items
+		}
+	}
+	if entry.Stack != nil {
+		items := make([]legacyFrameItem, 0, len(entry.Stack))
+		for _, frame := range entry.Stack {
+			items = append(items, legacyFrameItem{Func: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		data[that.fieldMap.Resolve(log.FieldKeyStack)] = items
+	}
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(!that.disableHTMLEscape)
+	if that.prettyPrint {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+type formatCase struct {
+	name  string
+	entry *log.Entry
+}
+
+type legacyStringer struct {
+	Name string `json:"name"`
+}
+
+func makeFormatCases() []formatCase {
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	return []formatCase{
+		{
+			name:  "message",
+			entry: &log.Entry{Time: tm, Level: log.InfoLevel, Message: "Hello, World!"},
+		},
+		{
+			name: "scalars",
+			entry: &log.Entry{
+				Time:    tm,
+				Level:   log.InfoLevel,
+				Message: "Hello, \"World\" <tag> &  \n",
+				Data: log.Fields{
+					"request_id": "r1",
+					"user_id":    42,
+					"ratio":      0.000000125,
+					"big":        float32(1e22),
+					"ok":         true,
+					"elapsed":    time.Millisecond,
+					"at":         tm,
+					"count":      uint16(7),
+					"nothing":    nil,
+					"invalid":    "\xff",
+				},
+			},
+		},
+		{
+			name: "nested",
+			entry: &log.Entry{
+				Time:    tm,
+				Level:   log.WarnLevel,
+				Message: "Hello, World!",
+				Data: log.Fields{
+					"user":   log.Fields{"name": "bob", "tags": []string{"a", "b"}},
+					"items":  []interface{}{1, "two", map[string]interface{}{"three": 3.5}},
+					"struct": legacyStringer{Name: "x"},
+					"bytes":  []byte("abc"),
+				},
+			},
+		},
+		{
+			name: "error",
+			entry: &log.Entry{
+				Time:    tm,
+				Level:   log.ErrorLevel,
+				Message: "Hello, World!",
+				LogErr:  "can not add field \"fn\"",
+				Data:    log.Fields{log.ErrorKey: fmt.Errorf("wrapped: %w", errors.New("failure"))},
+				Caller:  &runtime.Frame{Function: "main.main", File: "/app/main.go", Line: 12},
+				Stack:   log.StackTrace{{Function: "main.main", File: "/app/main.go", Line: 12}},
+			},
+		},
+		{
+			name: "typed",
+			entry: &log.Entry{
+				Time:    tm,
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+				Data:    log.Fields{"path": "/api"},
+				TypedFields: []log.Field{
+					log.String("request_id", "r1"),
+					log.Int64("user_id", 42),
+					log.Duration("elapsed", time.Millisecond),
+				},
+			},
+		},
+	}
+}
+
+func TestFormatterMatchesLegacy(t *testing.T) {
+	type Test struct {
+		name    string
+		builder *Builder
+	}
+
+	tests := []Test{
+		{name: "default", builder: NewBuilder()},
+		{name: "pretty", builder: NewBuilder().WithPrettyPrint(true)},
+		{name: "no html escape", builder: NewBuilder().WithDisableHTMLEscape(true).WithDisableTimestamp(true)},
+		{name: "field map", builder: NewBuilder().WithFieldMap(log.FieldMap{log.FieldKeyMsg: "message", log.FieldKeyTime: "data"})},
+	}
+
+	for _, test := range tests {
+		formatter, err := test.builder.Build()
+		require.NoError(t, err)
+		legacy := &legacyFormatter{Formatter: formatter}
+
+		for _, c := range makeFormatCases() {
+			t.Run(test.name+"/"+c.name, func(t *testing.T) {
+				expected, err := legacy.Format(c.entry)
+				require.NoError(t, err)
+				actual, err := formatter.Format(c.entry)
+				require.NoError(t, err)
+				require.Equal(t, string(expected), string(actual))
+			})
+		}
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	formatter, _ := NewBuilder().Build()
+	formatters := []struct {
+		name      string
+		formatter log.Formatter
+	}{
+		{name: "streaming", formatter: formatter},
+		{name: "legacy", formatter: &legacyFormatter{Formatter: formatter}},
+	}
+
+	for _, c := range makeFormatCases() {
+		for _, f := range formatters {
+			b.Run(c.name+"/"+f.name, func(b *testing.B) {
+				entry := *c.entry
+				entry.Buffer = &bytes.Buffer{}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					entry.Buffer.Reset()
+					data, err := f.formatter.Format(&entry)
+					if err != nil {
+						b.Fatal(err)
+					}
+					b.SetBytes(int64(len(data)))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package jsonFormatter
+
+type memberKind int
+
+const (
+	memberData memberKind = iota
+	memberLoggerError
+	memberTime
+	memberMsg
+	memberLevel
+	memberFunc
+	memberCaller
+	memberErrors
+	memberStack
+)
+
+type member struct {
+	key  string
+	kind memberKind
+}
+
+// members are the top-level members of the entry, sorted by key as encoding/json sorts maps.
+type members struct {
+	items [memberStack + 1]member
+	count int
+}
+
+// add adds the member. The member with the same key is replaced, as in the map.
+func (that *members) add(key string, kind memberKind) {
+	for i := 0; i < that.count; i++ {
+		if that.items[i].key == key {
+			that.items[i].kind = kind
+			return
+		}
+	}
+	that.items[that.count] = member{key: key, kind: kind}
+	that.count++
+}
+
+func (that *members) sort() {
+	for i := 1; i < that.count; i++ {
+		for j := i; j > 0 && that.items[j].key < that.items[j-1].key; j-- {
+			that.items[j], that.items[j-1] = that.items[j-1], that.items[j]
+		}
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package jsonFormatter
+
+const raceEnabled = false
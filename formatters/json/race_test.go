@@ -0,0 +1,7 @@
+//go:build race
+
+package jsonFormatter
+
+// raceEnabled is true, when tests are run by the race detector.
+// sync.Pool drops items randomly under it, so allocations are not measured.
+const raceEnabled = true
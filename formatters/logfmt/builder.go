@@ -0,0 +1,39 @@
+package logfmtFormatter
+
+import (
+	"github.com/adverax/log"
+	"time"
+)
+
+type Builder struct {
+	formatter *Formatter
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		formatter: &Formatter{
+			timestampFormat:  time.RFC3339,
+			disableTimestamp: false,
+			fieldMap:         log.FieldMap{},
+		},
+	}
+}
+
+func (that *Builder) WithTimestampFormat(timestampFormat string) *Builder {
+	that.formatter.timestampFormat = timestampFormat
+	return that
+}
+
+func (that *Builder) WithDisableTimestamp(disableTimestamp bool) *Builder {
+	that.formatter.disableTimestamp = disableTimestamp
+	return that
+}
+
+func (that *Builder) WithFieldMap(fieldMap log.FieldMap) *Builder {
+	that.formatter.fieldMap = fieldMap
+	return that
+}
+
+func (that *Builder) Build() (*Formatter, error) {
+	return that.formatter, nil
+}
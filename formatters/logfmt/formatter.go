@@ -0,0 +1,215 @@
+package logfmtFormatter
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"github.com/adverax/log"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const hex = "0123456789abcdef"
+
+// Formatter renders entries in logfmt: time=... level=info msg="..." key=value.
+// Members of the entry go first in fixed order, fields follow in the order of keys.
+type Formatter struct {
+	timestampFormat  string
+	disableTimestamp bool
+	fieldMap         log.FieldMap
+}
+
+// Format renders a single log entry
+func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
+	data := make(log.Fields, len(entry.Data)+len(entry.TypedFields))
+	for k, v := range entry.Fields() {
+		data[k] = v
+	}
+	that.fieldMap.EncodePrefixFieldClashes(data)
+	if entry.HasCaller() {
+		that.fieldMap.EncodePrefixCallerClashes(data)
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	if !that.disableTimestamp {
+		that.appendKey(b, that.fieldMap.Resolve(log.FieldKeyTime))
+		that.appendString(b, entry.Time.Format(that.timestampFormat))
+	}
+	that.appendKey(b, that.fieldMap.Resolve(log.FieldKeyLevel))
+	that.appendString(b, entry.Level.String())
+	that.appendKey(b, that.fieldMap.Resolve(log.FieldKeyMsg))
+	that.appendString(b, entry.Message)
+	if entry.LogErr != "" {
+		that.appendKey(b, that.fieldMap.Resolve(log.FieldKeyLoggerError))
+		that.appendString(b, entry.LogErr)
+	}
+	if entry.HasCaller() {
+		that.appendKey(b, that.fieldMap.Resolve(log.FieldKeyCaller))
+		that.appendString(b, log.FormatCaller(entry))
+		that.appendKey(b, that.fieldMap.Resolve(log.FieldKeyFunc))
+		that.appendString(b, entry.Caller.Function)
+	}
+	for _, k := range keys {
+		that.appendKey(b, k)
+		if err := that.appendValue(b, data[k]); err != nil {
+			return nil, fmt.Errorf("failed to marshal field %q to logfmt, %w", k, err)
+		}
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// appendKey writes the key followed by '='. Characters, that are not allowed in keys, are replaced by '_'.
+func (that *Formatter) appendKey(b *bytes.Buffer, key string) {
+	if b.Len() != 0 {
+		b.WriteByte(' ')
+	}
+	if key == "" {
+		key = "_"
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || r == 0x7f {
+			b.WriteByte('_')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('=')
+}
+
+func (that *Formatter) appendValue(b *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case string:
+		that.appendString(b, v)
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int:
+		b.WriteString(strconv.FormatInt(int64(v), 10))
+	case int32:
+		b.WriteString(strconv.FormatInt(int64(v), 10))
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case uint:
+		b.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		b.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		b.WriteString(strconv.FormatUint(v, 10))
+	case float32:
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case time.Duration:
+		b.WriteString(v.String())
+	case time.Time:
+		that.appendString(b, v.Format(time.RFC3339Nano))
+	case error:
+		that.appendString(b, v.Error())
+	case fmt.Stringer:
+		that.appendString(b, v.String())
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return err
+		}
+		that.appendString(b, string(text))
+	default:
+		// Maps, slices and structs are written as quoted JSON.
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		that.appendString(b, string(encoded))
+	}
+	return nil
+}
+
+// appendString writes the value, that is quoted when it contains spaces, quotes,
+// equal signs or characters, that are not printable.
+func (that *Formatter) appendString(b *bytes.Buffer, s string) {
+	if !needsQuoting(s) {
+		b.WriteString(s)
+		return
+	}
+
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				b.WriteString(s[start:i])
+				b.WriteString(`\ufffd`)
+				start = i + size
+			}
+			i += size
+			continue
+		}
+		if c >= ' ' && c != '"' && c != '\\' && c != 0x7f {
+			i++
+			continue
+		}
+
+		b.WriteString(s[start:i])
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteString(`\u00`)
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xF])
+		}
+		i++
+		start = i
+	}
+	b.WriteString(s[start:])
+	b.WriteByte('"')
+}
+
+func needsQuoting(s string) bool {
+	if s == "" || s == "null" {
+		return true
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				return true
+			}
+			i += size
+			continue
+		}
+		if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+			return true
+		}
+		i++
+	}
+	return false
+}
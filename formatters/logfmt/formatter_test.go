@@ -0,0 +1,77 @@
+package logfmtFormatter
+
+import (
+	"errors"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFormatter(t *testing.T) {
+	type Test struct {
+		name     string
+		builder  *Builder
+		entry    *log.Entry
+		expected string
+	}
+
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []Test{
+		{
+			name:    "info",
+			builder: NewBuilder(),
+			entry: &log.Entry{
+				Time:    tm,
+				Level:   log.InfoLevel,
+				Message: "Hello, World!",
+			},
+			expected: `time=2024-01-02T03:04:05Z level=info msg="Hello, World!"` + "\n",
+		},
+		{
+			name:    "fields",
+			builder: NewBuilder().WithDisableTimestamp(true),
+			entry: &log.Entry{
+				Level:   log.ErrorLevel,
+				Message: "failure",
+				Data: log.Fields{
+					"zoo":      1,
+					"empty":    "",
+					"quoted":   `say "hi"` + "\n",
+					"error":    errors.New("bad thing"),
+					"elapsed":  1500 * time.Millisecond,
+					"ratio":    0.5,
+					"ok":       true,
+					"nothing":  nil,
+					"tags":     []string{"a", "b"},
+					"bad key=": "x",
+				},
+				TypedFields: []log.Field{log.Int64("count", 2)},
+			},
+			expected: `level=error msg=failure bad_key_=x count=2 elapsed=1.5s empty="" error="bad thing" nothing=null ok=true quoted="say \"hi\"\n" ratio=0.5 tags="[\"a\",\"b\"]" zoo=1` + "\n",
+		},
+		{
+			name:    "clashes and caller",
+			builder: NewBuilder().WithDisableTimestamp(true).WithFieldMap(log.FieldMap{log.FieldKeyMsg: "message"}),
+			entry: &log.Entry{
+				Level:   log.InfoLevel,
+				Message: "Hello",
+				LogErr:  "can not add field",
+				Data:    log.Fields{"message": "clash", "caller": "clash"},
+				Caller:  &runtime.Frame{Function: "main.main", File: "/app/main.go", Line: 12},
+			},
+			expected: `level=info message=Hello logger_error="can not add field" caller=/app/main.go:12 func=main.main fields.caller=clash fields.message=clash` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formatter, err := test.builder.Build()
+			require.NoError(t, err)
+			data, err := formatter.Format(test.entry)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(data))
+		})
+	}
+}
@@ -2,32 +2,57 @@ package template
 
 import (
 	"errors"
+	"fmt"
 	"github.com/adverax/log"
-	"strings"
 	"text/template"
 )
 
 type Builder struct {
-	formatter *Formatter
+	formatter    *Formatter
+	templateText string
+	funcs        template.FuncMap
 }
 
 func NewBuilder() *Builder {
 	return &Builder{
 		formatter: &Formatter{
-			timestampFormat:  log.DefaultTimestampFormat,
-			disableTimestamp: false,
-			template:         defaultTpl,
-			systemFields:     defaultSystemFields,
-			fieldMap:         log.FieldMap{},
+			timestampFormat:        log.DefaultTimestampFormat,
+			disableTimestamp:       false,
+			disableLevelTruncation: true,
+			padLevelText:           false,
+			template:               defaultTpl,
+			systemFields:           defaultSystemFields,
+			fieldMap:               log.FieldMap{},
 		},
 	}
 }
 
+// WithTemplate sets the parsed template. Functions of Funcs must be registered
+// before parsing. Functions of WithFuncs replace functions with the same names
+// in the copy of template made by Build, so the template itself is not modified.
 func (that *Builder) WithTemplate(tpl *template.Template) *Builder {
 	that.formatter.template = tpl
 	return that
 }
 
+// WithTemplateText sets the text of template, that is parsed by Build
+// with functions of Funcs and WithFuncs.
+func (that *Builder) WithTemplateText(text string) *Builder {
+	that.templateText = text
+	return that
+}
+
+// WithFuncs adds functions available to the template. They take precedence over Funcs.
+func (that *Builder) WithFuncs(funcs template.FuncMap) *Builder {
+	if that.funcs == nil {
+		that.funcs = make(template.FuncMap, len(funcs))
+	}
+	for name, fn := range funcs {
+		that.funcs[name] = fn
+	}
+	return that
+}
+
 func (that *Builder) WithDisableTimestamp(disableTimestamp bool) *Builder {
 	that.formatter.disableTimestamp = disableTimestamp
 	return that
@@ -53,17 +78,24 @@ func (that *Builder) WithSortingFunc(sortingFunc func([]string)) *Builder {
 	return that
 }
 
+// WithDisableLevelTruncation disables truncation of the level to 4 characters.
+// Truncation is disabled by default.
 func (that *Builder) WithDisableLevelTruncation(disableLevelTruncation bool) *Builder {
 	that.formatter.disableLevelTruncation = disableLevelTruncation
 	return that
 }
 
+// WithPadLevelText pads the level by spaces to the length of the longest level,
+// so messages are aligned.
 func (that *Builder) WithPadLevelText(padLevelText bool) *Builder {
 	that.formatter.padLevelText = padLevelText
 	return that
 }
 
 func (that *Builder) Build() (*Formatter, error) {
+	if err := that.buildTemplate(); err != nil {
+		return nil, err
+	}
 	if err := that.checkRequiredFields(); err != nil {
 		return nil, err
 	}
@@ -71,6 +103,30 @@ func (that *Builder) Build() (*Formatter, error) {
 	return that.formatter, nil
 }
 
+func (that *Builder) buildTemplate() error {
+	if that.templateText != "" {
+		funcs := Funcs()
+		for name, fn := range that.funcs {
+			funcs[name] = fn
+		}
+		tpl, err := template.New("log").Funcs(funcs).Parse(that.templateText)
+		if err != nil {
+			return fmt.Errorf("%w, %v", ErrInvalidTemplate, err)
+		}
+		that.formatter.template = tpl
+		return nil
+	}
+
+	if that.funcs != nil && that.formatter.template != nil {
+		tpl, err := that.formatter.template.Clone()
+		if err != nil {
+			return fmt.Errorf("%w, %v", ErrInvalidTemplate, err)
+		}
+		that.formatter.template = tpl.Funcs(that.funcs)
+	}
+	return nil
+}
+
 func (that *Builder) checkRequiredFields() error {
 	if that.formatter.template == nil {
 		return ErrTemplateRequired
@@ -81,15 +137,12 @@ func (that *Builder) checkRequiredFields() error {
 
 var (
 	ErrTemplateRequired = errors.New("template is required")
+	ErrInvalidTemplate  = errors.New("invalid template")
 )
 
-var funcMap = template.FuncMap{
-	"ToUpper": strings.ToUpper,
-}
-
-var defaultTemplate = `{{.time}} {{.level | ToUpper}}{{if .trace_id}} #{{.trace_id}}{{end}}:{{.entity}} {{.msg}}{{.event}}{{if .details}} DETAILS {{.details}}{{end}}`
+var defaultTemplate = `{{.time}} {{.level | ToUpper}}{{if .logger}} [{{.logger}}]{{end}}{{if .caller}} <{{.caller}}>{{end}}{{if .trace_id}} #{{.trace_id}}{{end}}:{{.entity}} {{.msg}}{{.event}}{{if .details}} DETAILS {{.details}}{{end}}`
 
-var defaultTpl = template.Must(template.New("log").Funcs(funcMap).Parse(defaultTemplate))
+var defaultTpl = template.Must(template.New("log").Funcs(Funcs()).Parse(defaultTemplate))
 
 var defaultSystemFields = map[string]struct{}{
 	log.FieldKeyTime:    {},
@@ -101,4 +154,7 @@ var defaultSystemFields = map[string]struct{}{
 	log.FieldKeyMethod:  {},
 	log.FieldKeySubject: {},
 	log.FieldKeyData:    {},
+	log.FieldKeyLogger:  {},
+	log.FieldKeyCaller:  {},
+	log.FieldKeyFunc:    {},
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/adverax/log"
 	"sort"
+	"strings"
 	"text/template"
 )
 
@@ -13,6 +14,8 @@ type Purifier interface {
 	Purify(original, derivative string) string
 }
 
+const paramFields = "fields"
+
 // Formatter formats logs into text
 type Formatter struct {
 	purifier               Purifier
@@ -30,10 +33,13 @@ type Formatter struct {
 // Format renders a single log entry
 func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
 	data := make(log.Fields)
-	for k, v := range entry.Data {
+	for k, v := range entry.Fields() {
 		data[k] = v
 	}
 	that.fieldMap.EncodePrefixFieldClashes(data)
+	if entry.HasCaller() {
+		that.fieldMap.EncodePrefixCallerClashes(data)
+	}
 	keys := make([]string, 0, len(data))
 	for k := range data {
 		keys = append(keys, k)
@@ -50,6 +56,9 @@ func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
 	if entry.LogErr != "" {
 		fixedKeys = append(fixedKeys, that.fieldMap.Resolve(log.FieldKeyLoggerError))
 	}
+	if entry.HasCaller() {
+		fixedKeys = append(fixedKeys, that.fieldMap.Resolve(log.FieldKeyCaller), that.fieldMap.Resolve(log.FieldKeyFunc))
+	}
 
 	if !that.disableSorting {
 		if that.sortingFunc == nil {
@@ -92,11 +101,15 @@ func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
 		case key == that.fieldMap.Resolve(log.FieldKeyTime):
 			value = entry.Time.Format(timestampFormat)
 		case key == that.fieldMap.Resolve(log.FieldKeyLevel):
-			value = entry.Level.String()
+			value = log.FormatLevel(entry.Level, !that.disableLevelTruncation, that.padLevelText)
 		case key == that.fieldMap.Resolve(log.FieldKeyMsg):
 			value = that.purify(entry.Message)
 		case key == that.fieldMap.Resolve(log.FieldKeyLoggerError):
 			value = entry.LogErr
+		case key == that.fieldMap.Resolve(log.FieldKeyCaller) && entry.HasCaller():
+			value = log.FormatCaller(entry)
+		case key == that.fieldMap.Resolve(log.FieldKeyFunc) && entry.HasCaller():
+			value = entry.Caller.Function
 		case key == that.fieldMap.Resolve(log.FieldKeyTraceID):
 			value, _ = data[key]
 			continue
@@ -132,6 +145,8 @@ func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
 		}
 	}
 
+	// Raw values of fields are available to templates as {{.fields.key}}.
+	params[paramFields] = data
 	params["entity"] = that.formatEntity(entity, action)
 	params["event"] = that.formatEvent(method, subject, body)
 
@@ -152,9 +167,44 @@ func (that *Formatter) Format(entry *log.Entry) ([]byte, error) {
 	_ = tpl.Execute(b, params)
 
 	b.WriteByte('\n')
+	that.writeErrors(b, entry)
+	that.writeStack(b, entry.Stack)
 	return b.Bytes(), nil
 }
 
+// writeErrors writes the chain of the entry error as indented lines,
+// when it wraps other errors.
+func (that *Formatter) writeErrors(b *bytes.Buffer, entry *log.Entry) {
+	err := entry.ErrorValue()
+	if err == nil {
+		return
+	}
+
+	chain := log.ErrorChain(err)
+	if len(chain) < 2 {
+		return
+	}
+
+	b.WriteString("\terrors:\n")
+	for _, item := range chain {
+		b.WriteByte('\t')
+		b.WriteString(strings.Repeat("  ", item.Depth+1))
+		b.WriteString(that.purify(item.Message))
+		b.WriteByte('\n')
+	}
+}
+
+func (that *Formatter) writeStack(b *bytes.Buffer, stack log.StackTrace) {
+	if len(stack) == 0 {
+		return
+	}
+
+	b.WriteString("\tstack:\n")
+	for _, frame := range stack {
+		fmt.Fprintf(b, "\t  %s\n\t    %s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+}
+
 func (that *Formatter) value2string(value interface{}) string {
 	stringVal, ok := value.(string)
 	if !ok {
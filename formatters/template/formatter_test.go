@@ -1,10 +1,14 @@
 package template
 
 import (
+	"errors"
+	"fmt"
 	"github.com/adverax/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+	"text/template"
 	"time"
 )
 
@@ -28,6 +32,25 @@ func TestFormatter(t *testing.T) {
 			},
 			expected: "0001/01/01 00:00:00 INFO: Hello, World! DETAILS {\"key\":\"value\"}\n",
 		},
+		{
+			name: "error chain",
+			entry: &log.Entry{
+				Time:    time.Time{},
+				Level:   log.ErrorLevel,
+				Message: "Hello, World!",
+				Data: log.Fields{
+					log.ErrorKey: fmt.Errorf("outer: %w", errors.New("inner")),
+				},
+				Stack: log.StackTrace{{Function: "main.main", File: "/app/main.go", Line: 12}},
+			},
+			expected: "0001/01/01 00:00:00 ERROR: Hello, World! DETAILS {\"error\":\"outer: inner\"}\n" +
+				"\terrors:\n" +
+				"\t  outer: inner\n" +
+				"\t    inner\n" +
+				"\tstack:\n" +
+				"\t  main.main\n" +
+				"\t    /app/main.go:12\n",
+		},
 	}
 
 	formatter, err := NewBuilder().
@@ -43,3 +66,104 @@ func TestFormatter(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatterLevelText(t *testing.T) {
+	type Test struct {
+		name     string
+		builder  *Builder
+		level    log.Level
+		expected string
+	}
+
+	tests := []Test{
+		{name: "default", builder: NewBuilder(), level: log.InfoLevel, expected: "INFO: Hello\n"},
+		{name: "padded", builder: NewBuilder().WithPadLevelText(true), level: log.InfoLevel, expected: "INFO : Hello\n"},
+		{name: "truncated", builder: NewBuilder().WithDisableLevelTruncation(false), level: log.ErrorLevel, expected: "ERRO: Hello\n"},
+		{name: "truncated and padded", builder: NewBuilder().WithDisableLevelTruncation(false).WithPadLevelText(true), level: log.WarnLevel, expected: "WARN: Hello\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formatter, err := test.builder.WithTimestampFormat("15:04").Build()
+			require.NoError(t, err)
+			data, err := formatter.Format(&log.Entry{Level: test.level, Message: "Hello"})
+			require.NoError(t, err)
+			assert.Equal(t, "00:00 "+test.expected, string(data))
+		})
+	}
+}
+
+func TestFormatterFields(t *testing.T) {
+	formatter, err := NewBuilder().
+		WithTemplateText(`{{.level | ToUpper | pad 6}}|{{.fields.user_id | padLeft 4}}|{{.fields.path | default "-"}}|{{.fields.elapsed | humanizeDuration}}|{{.fields.at | formatTime "15:04"}}|{{.fields.tags | json}}|{{.msg | truncate 5 | color "red"}}|{{.fields.user_id | twice}}`).
+		WithFuncs(template.FuncMap{
+			"twice": func(value int) int { return value * 2 },
+		}).
+		Build()
+	require.NoError(t, err)
+
+	data, err := formatter.Format(&log.Entry{
+		Level:   log.InfoLevel,
+		Message: "Hello, World!",
+		Data: log.Fields{
+			"user_id": 42,
+			"elapsed": 150 * time.Second,
+			"at":      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			"tags":    []string{"a"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "INFO  |  42|-|2m30s|03:04|[\"a\"]|\x1b[31mHello\x1b[0m|84\n", string(data))
+}
+
+func TestBuilderFuncsDoNotModifyTemplate(t *testing.T) {
+	tpl := template.Must(template.New("log").Funcs(template.FuncMap{
+		"mark": func(s string) string { return "*" + s },
+	}).Parse(`{{.msg | mark}}`))
+
+	formatter, err := NewBuilder().
+		WithTemplate(tpl).
+		WithFuncs(template.FuncMap{
+			"mark": func(s string) string { return "!" + s },
+		}).
+		Build()
+	require.NoError(t, err)
+
+	data, err := formatter.Format(&log.Entry{Level: log.InfoLevel, Message: "Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "!Hello\n", string(data))
+
+	var b strings.Builder
+	require.NoError(t, tpl.Execute(&b, map[string]interface{}{"msg": "Hello"}))
+	assert.Equal(t, "*Hello", b.String())
+}
+
+func TestBuilderInvalidTemplate(t *testing.T) {
+	_, err := NewBuilder().WithTemplateText(`{{.msg | unknown}}`).Build()
+	require.ErrorIs(t, err, ErrInvalidTemplate)
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	type Test struct {
+		value    interface{}
+		expected string
+	}
+
+	tests := []Test{
+		{value: 1500 * time.Nanosecond, expected: "1.5µs"},
+		{value: 1234567 * time.Microsecond, expected: "1.235s"},
+		{value: 2 * time.Minute, expected: "2m"},
+		{value: 3*time.Hour + 5*time.Minute + 10*time.Second, expected: "3h5m"},
+		{value: 3 * time.Hour, expected: "3h"},
+		{value: 50 * time.Hour, expected: "2d2h"},
+		{value: -time.Second, expected: "-1s"},
+		{value: "90s", expected: "1m30s"},
+		{value: int64(time.Millisecond), expected: "1ms"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, humanizeDuration(test.value))
+		})
+	}
+}
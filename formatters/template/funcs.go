@@ -0,0 +1,195 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+var colors = map[string]string{
+	"black":   "\x1b[30m",
+	"red":     "\x1b[31m",
+	"green":   "\x1b[32m",
+	"yellow":  "\x1b[33m",
+	"blue":    "\x1b[34m",
+	"magenta": "\x1b[35m",
+	"cyan":    "\x1b[36m",
+	"white":   "\x1b[37m",
+	"gray":    "\x1b[90m",
+	"bold":    "\x1b[1m",
+	"faint":   "\x1b[2m",
+}
+
+// Funcs returns the functions available to templates of the formatter.
+// Use it to parse custom templates:
+//
+//	template.New("log").Funcs(templateFormatter.Funcs()).Parse(text)
+//
+// Functions take the piped value as the last argument, e.g. {{.msg | truncate 20 | pad 20}}.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"ToUpper":          strings.ToUpper,
+		"ToLower":          strings.ToLower,
+		"trim":             strings.TrimSpace,
+		"pad":              pad,
+		"padLeft":          padLeft,
+		"truncate":         truncate,
+		"color":            color,
+		"json":             toJSON,
+		"default":          defaultValue,
+		"formatTime":       formatTime,
+		"humanizeDuration": humanizeDuration,
+	}
+}
+
+// pad pads the value by spaces on the right up to width characters.
+func pad(width int, value interface{}) string {
+	s := toString(value)
+	if n := utf8.RuneCountInString(s); n < width {
+		s += strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// padLeft pads the value by spaces on the left up to width characters.
+func padLeft(width int, value interface{}) string {
+	s := toString(value)
+	if n := utf8.RuneCountInString(s); n < width {
+		s = strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// truncate cuts the value to width characters.
+func truncate(width int, value interface{}) string {
+	s := toString(value)
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width])
+}
+
+// color wraps the value by ANSI codes of the color, e.g. "red" or "bold".
+// Unknown colors leave the value as is.
+func color(name string, value interface{}) string {
+	s := toString(value)
+	code, ok := colors[name]
+	if !ok {
+		return s
+	}
+	return code + s + "\x1b[0m"
+}
+
+func toJSON(value interface{}) string {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("!ERROR: %v", err)
+	}
+	return string(data)
+}
+
+// defaultValue returns def, when the value is missing or empty.
+func defaultValue(def, value interface{}) interface{} {
+	if value == nil {
+		return def
+	}
+	if v := reflect.ValueOf(value); v.IsZero() {
+		return def
+	}
+	return value
+}
+
+// formatTime formats the time by layout. Strings are parsed as RFC 3339.
+func formatTime(layout string, value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout)
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return v
+		}
+		return t.Format(layout)
+	default:
+		return toString(value)
+	}
+}
+
+// humanizeDuration rounds the duration for humans, e.g. 1.235s, 2m30s or 3d4h.
+// Integers are treated as nanoseconds and strings are parsed by time.ParseDuration.
+func humanizeDuration(value interface{}) string {
+	var d time.Duration
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+	case int64:
+		d = time.Duration(v)
+	case int:
+		d = time.Duration(v)
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return v
+		}
+		d = parsed
+	default:
+		return toString(value)
+	}
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	switch {
+	case d < time.Millisecond:
+		return sign + d.String()
+	case d < time.Minute:
+		return sign + d.Round(time.Millisecond).String()
+	case d < time.Hour:
+		return sign + trimZeroUnits(d.Round(time.Second).String())
+	case d < 24*time.Hour:
+		return sign + trimZeroUnits(d.Round(time.Minute).String())
+	}
+
+	d = d.Round(time.Hour)
+	days := d / (24 * time.Hour)
+	hours := (d % (24 * time.Hour)) / time.Hour
+	s := sign + strconv.FormatInt(int64(days), 10) + "d"
+	if hours != 0 {
+		s += strconv.FormatInt(int64(hours), 10) + "h"
+	}
+	return s
+}
+
+// trimZeroUnits removes zero seconds and minutes, e.g. 3h0m0s becomes 3h.
+func trimZeroUnits(s string) string {
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
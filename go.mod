@@ -4,8 +4,10 @@ go 1.21.6
 
 require (
 	github.com/adverax/enums v1.0.0
+	github.com/klauspost/compress v1.17.11
 	github.com/olivere/elastic/v7 v7.0.32
 	github.com/stretchr/testify v1.10.0
+	go.opentelemetry.io/otel/trace v1.28.0
 )
 
 require (
@@ -14,6 +16,7 @@ require (
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/otel v1.28.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,49 @@
+package levelHandler
+
+import (
+	"errors"
+	"time"
+)
+
+type Builder struct {
+	handler *Handler
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		handler: &Handler{
+			loggers: make(map[string]*logger),
+		},
+	}
+}
+
+// WithLogger registers the logger under the name.
+func (that *Builder) WithLogger(name string, leveler Leveler) *Builder {
+	that.handler.loggers[name] = &logger{leveler: leveler}
+	return that
+}
+
+// WithMaxTTL limits the TTL, that can be requested for a temporary level change.
+func (that *Builder) WithMaxTTL(maxTTL time.Duration) *Builder {
+	that.handler.maxTTL = maxTTL
+	return that
+}
+
+func (that *Builder) Build() (*Handler, error) {
+	if err := that.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+
+	return that.handler, nil
+}
+
+func (that *Builder) checkRequiredFields() error {
+	if len(that.handler.loggers) == 0 {
+		return ErrRequiredFieldLoggers
+	}
+	return nil
+}
+
+var (
+	ErrRequiredFieldLoggers = errors.New("loggers are required")
+)
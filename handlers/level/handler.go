@@ -0,0 +1,253 @@
+package levelHandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/adverax/log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Leveler is a logger, whose level can be changed at runtime.
+type Leveler interface {
+	GetLevel() log.Level
+	SetLevel(level log.Level)
+}
+
+// OverriddenLeveler is a logger, whose level may be overridden by rules
+// of log.LevelRegistry or inherited from the parent, e.g. *log.Log.
+type OverriddenLeveler interface {
+	Leveler
+	// BaseLevel returns the level, that is set by SetLevel.
+	BaseLevel() log.Level
+	// LevelOverride returns the level of the rule, that takes precedence over the base level.
+	LevelOverride() (log.Level, bool)
+	// InheritsLevel reports whether the level is inherited from the parent.
+	InheritsLevel() bool
+	// ResetLevel makes the logger to inherit the level of the parent again.
+	ResetLevel()
+}
+
+type logger struct {
+	leveler   Leveler
+	timer     *time.Timer
+	revert    log.Level
+	inherited bool
+	expiresAt time.Time
+}
+
+// Handler exposes levels of the registered loggers over HTTP.
+//
+// GET returns levels of loggers selected by "name" query parameters (all by default).
+// PUT changes levels by the JSON request like
+//
+//	{"names": ["db"], "level": "debug", "ttl": "10m"}
+//
+// When ttl is set, the previous level is restored after it expires.
+type Handler struct {
+	mu      sync.Mutex
+	loggers map[string]*logger
+	maxTTL  time.Duration
+}
+
+// LoggerState is the state of logger. Level is the level set by PUT.
+// Override is the level of the rule of the level registry, that takes precedence over Level.
+type LoggerState struct {
+	Name      string     `json:"name"`
+	Level     string     `json:"level"`
+	Override  string     `json:"override,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type Response struct {
+	Loggers []LoggerState `json:"loggers"`
+}
+
+type Request struct {
+	Names []string `json:"names,omitempty"`
+	Level string   `json:"level"`
+	TTL   string   `json:"ttl,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (that *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		that.get(w, r)
+	case http.MethodPut:
+		that.put(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		that.fail(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+func (that *Handler) get(w http.ResponseWriter, r *http.Request) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	names, err := that.resolveNames(r.URL.Query()["name"])
+	if err != nil {
+		that.fail(w, http.StatusNotFound, err)
+		return
+	}
+
+	that.reply(w, names)
+}
+
+func (that *Handler) put(w http.ResponseWriter, r *http.Request) {
+	var request Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		that.fail(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	level, err := log.ParseLevel(request.Level)
+	if err != nil {
+		that.fail(w, http.StatusBadRequest, fmt.Errorf("invalid level %q", request.Level))
+		return
+	}
+
+	var ttl time.Duration
+	if request.TTL != "" {
+		ttl, err = time.ParseDuration(request.TTL)
+		if err != nil || ttl < 0 {
+			that.fail(w, http.StatusBadRequest, fmt.Errorf("invalid ttl %q", request.TTL))
+			return
+		}
+		if that.maxTTL > 0 && ttl > that.maxTTL {
+			that.fail(w, http.StatusBadRequest, fmt.Errorf("ttl %s exceeds %s", ttl, that.maxTTL))
+			return
+		}
+	}
+
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	names, err := that.resolveNames(request.Names)
+	if err != nil {
+		that.fail(w, http.StatusNotFound, err)
+		return
+	}
+
+	for _, name := range names {
+		that.setLevel(name, level, ttl)
+	}
+
+	that.reply(w, names)
+}
+
+func (that *Handler) setLevel(name string, level log.Level, ttl time.Duration) {
+	l := that.loggers[name]
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	} else if ttl > 0 {
+		l.revert = baseLevel(l.leveler)
+		l.inherited = inheritsLevel(l.leveler)
+	}
+
+	l.expiresAt = time.Time{}
+	l.leveler.SetLevel(level)
+
+	if ttl > 0 {
+		l.expiresAt = time.Now().Add(ttl)
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			that.expire(l, timer)
+		})
+		l.timer = timer
+	}
+}
+
+func (that *Handler) expire(l *logger, timer *time.Timer) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	if l.timer != timer {
+		return
+	}
+
+	if leveler, ok := l.leveler.(OverriddenLeveler); ok && l.inherited {
+		leveler.ResetLevel()
+	} else {
+		l.leveler.SetLevel(l.revert)
+	}
+	l.timer = nil
+	l.expiresAt = time.Time{}
+}
+
+// baseLevel returns the level of the logger without overrides, so it is restored
+// after ttl instead of the override.
+func baseLevel(leveler Leveler) log.Level {
+	if leveler, ok := leveler.(OverriddenLeveler); ok {
+		return leveler.BaseLevel()
+	}
+	return leveler.GetLevel()
+}
+
+// inheritsLevel reports whether the logger inherits the level, so inheritance
+// is restored after ttl instead of the copy of the inherited level.
+func inheritsLevel(leveler Leveler) bool {
+	if leveler, ok := leveler.(OverriddenLeveler); ok {
+		return leveler.InheritsLevel()
+	}
+	return false
+}
+
+func (that *Handler) resolveNames(names []string) ([]string, error) {
+	if len(names) == 0 {
+		names = make([]string, 0, len(that.loggers))
+		for name := range that.loggers {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := that.loggers[name]; !ok {
+			return nil, fmt.Errorf("logger %q not found", name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (that *Handler) reply(w http.ResponseWriter, names []string) {
+	response := Response{Loggers: make([]LoggerState, 0, len(names))}
+	for _, name := range names {
+		l := that.loggers[name]
+		state := LoggerState{
+			Name:  name,
+			Level: baseLevel(l.leveler).String(),
+		}
+		if leveler, ok := l.leveler.(OverriddenLeveler); ok {
+			if override, ok := leveler.LevelOverride(); ok {
+				state.Override = override.String()
+			}
+		}
+		if !l.expiresAt.IsZero() {
+			expiresAt := l.expiresAt
+			state.ExpiresAt = &expiresAt
+		}
+		response.Loggers = append(response.Loggers, state)
+	}
+
+	that.write(w, http.StatusOK, response)
+}
+
+func (that *Handler) fail(w http.ResponseWriter, status int, err error) {
+	that.write(w, status, errorResponse{Error: err.Error()})
+}
+
+func (that *Handler) write(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
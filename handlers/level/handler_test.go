@@ -0,0 +1,127 @@
+package levelHandler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	db := log.NewDummyLogger()
+	api := log.NewDummyLogger()
+
+	handler, err := NewBuilder().
+		WithLogger("db", db).
+		WithLogger("api", api).
+		Build()
+	require.NoError(t, err)
+
+	call := func(method, target, body string) (int, Response) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		var response Response
+		_ = json.Unmarshal(rec.Body.Bytes(), &response)
+		return rec.Code, response
+	}
+
+	code, response := call(http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []LoggerState{{Name: "api", Level: "info"}, {Name: "db", Level: "info"}}, response.Loggers)
+
+	code, _ = call(http.MethodGet, "/?name=cache", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = call(http.MethodPut, "/", `{"level":"loud"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, response = call(http.MethodPut, "/", `{"names":["db"],"level":"trace","ttl":"50ms"}`)
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, response.Loggers, 1)
+	assert.Equal(t, "trace", response.Loggers[0].Level)
+	assert.NotNil(t, response.Loggers[0].ExpiresAt)
+	assert.Equal(t, log.TraceLevel, db.GetLevel())
+	assert.Equal(t, log.InfoLevel, api.GetLevel())
+
+	assert.Eventually(t, func() bool {
+		_, response := call(http.MethodGet, "/?name=db", "")
+		return response.Loggers[0].Level == "info" && response.Loggers[0].ExpiresAt == nil
+	}, time.Second, 10*time.Millisecond)
+
+	code, _ = call(http.MethodDelete, "/", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+type nopExporter struct{}
+
+func (that nopExporter) Export(ctx context.Context, entry *log.Entry) {}
+
+func TestHandlerLevelOverride(t *testing.T) {
+	registry := log.NewLevelRegistry()
+	db, err := log.NewBuilder().
+		WithName("db").
+		WithLevel(log.InfoLevel).
+		WithLevelRegistry(registry).
+		WithExporter(nopExporter{}).
+		Build()
+	require.NoError(t, err)
+
+	handler, err := NewBuilder().WithLogger("db", db).Build()
+	require.NoError(t, err)
+
+	call := func(method, body string) Response {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	registry.Set("db", log.ErrorLevel)
+	assert.Equal(t, []LoggerState{{Name: "db", Level: "info", Override: "error"}}, call(http.MethodGet, "").Loggers)
+
+	response := call(http.MethodPut, `{"level":"debug","ttl":"50ms"}`)
+	assert.Equal(t, "debug", response.Loggers[0].Level)
+	assert.Equal(t, "error", response.Loggers[0].Override)
+
+	// The base level is restored, not the override.
+	assert.Eventually(t, func() bool {
+		return call(http.MethodGet, "").Loggers[0].ExpiresAt == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, log.InfoLevel, db.BaseLevel())
+
+	registry.Unset("db")
+	assert.Equal(t, log.InfoLevel, db.GetLevel())
+}
+
+func TestHandlerRestoresInheritedLevel(t *testing.T) {
+	root, err := log.NewBuilder().
+		WithLevel(log.InfoLevel).
+		WithExporter(nopExporter{}).
+		Build()
+	require.NoError(t, err)
+	db := root.Named("db")
+
+	handler, err := NewBuilder().WithLogger("db", db).Build()
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug","ttl":"50ms"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, log.DebugLevel, db.GetLevel())
+
+	assert.Eventually(t, func() bool {
+		return db.GetLevel() == log.InfoLevel
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, db.InheritsLevel())
+
+	root.SetLevel(log.WarnLevel)
+	assert.Equal(t, log.WarnLevel, db.GetLevel())
+}
@@ -2,6 +2,8 @@ package log
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"sync"
 )
 
@@ -17,7 +19,9 @@ func (fn HookFunc) Fire(ctx context.Context, entry *Entry) error {
 
 type Hooks struct {
 	sync.RWMutex
-	hooks map[Level][]Hook
+	parent *Hooks
+	hooks  map[Level][]Hook
+	all    []Hook
 }
 
 func NewHooks() *Hooks {
@@ -26,6 +30,13 @@ func NewHooks() *Hooks {
 	}
 }
 
+// Child returns hooks, that fire hooks of the parent before their own.
+func (that *Hooks) Child() *Hooks {
+	child := NewHooks()
+	child.parent = that
+	return child
+}
+
 func (that *Hooks) Add(levels []Level, hook Hook) {
 	that.Lock()
 	defer that.Unlock()
@@ -33,9 +44,45 @@ func (that *Hooks) Add(levels []Level, hook Hook) {
 	for _, level := range levels {
 		that.hooks[level] = append(that.hooks[level], hook)
 	}
+	if !containsHook(that.all, hook) {
+		that.all = append(that.all, hook)
+	}
+}
+
+// containsHook reports whether the hook is registered already, so it is flushed
+// and closed once. Hooks of incomparable types, e.g. HookFunc, are never equal.
+func containsHook(hooks []Hook, hook Hook) bool {
+	if !reflect.TypeOf(hook).Comparable() {
+		return false
+	}
+
+	for _, h := range hooks {
+		if reflect.TypeOf(h) == reflect.TypeOf(hook) && h == hook {
+			return true
+		}
+	}
+	return false
+}
+
+// Has reports whether hooks are registered for the level.
+func (that *Hooks) Has(level Level) bool {
+	if that.parent != nil && that.parent.Has(level) {
+		return true
+	}
+
+	that.RLock()
+	defer that.RUnlock()
+
+	return len(that.hooks[level]) != 0
 }
 
 func (that *Hooks) Fire(ctx context.Context, level Level, entry *Entry) error {
+	if that.parent != nil {
+		if err := that.parent.Fire(ctx, level, entry); err != nil {
+			return err
+		}
+	}
+
 	that.RLock()
 	defer that.RUnlock()
 
@@ -47,3 +94,29 @@ func (that *Hooks) Fire(ctx context.Context, level Level, entry *Entry) error {
 
 	return nil
 }
+
+// Flush flushes every registered hook that implements Flusher.
+// Hooks of the parent are not flushed.
+func (that *Hooks) Flush(ctx context.Context) error {
+	that.RLock()
+	defer that.RUnlock()
+
+	var errs []error
+	for _, hook := range that.all {
+		errs = append(errs, Flush(ctx, hook))
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every registered hook that implements Closer.
+// Hooks of the parent are not closed.
+func (that *Hooks) Close(ctx context.Context) error {
+	that.RLock()
+	defer that.RUnlock()
+
+	var errs []error
+	for _, hook := range that.all {
+		errs = append(errs, Close(ctx, hook))
+	}
+	return errors.Join(errs...)
+}
@@ -21,3 +21,11 @@ func (that *HookReplica) Fire(ctx context.Context, entry *log.Entry) error {
 	that.exporter.Export(ctx, entry)
 	return nil
 }
+
+func (that *HookReplica) Flush(ctx context.Context) error {
+	return log.Flush(ctx, that.exporter)
+}
+
+func (that *HookReplica) Close(ctx context.Context) error {
+	return log.Close(ctx, that.exporter)
+}
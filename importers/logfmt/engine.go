@@ -0,0 +1,180 @@
+package logfmtImporter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/adverax/log"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Engine parses lines written by logfmtFormatter back into entries.
+// Values of fields are stored as strings, except of null.
+// The caller is restored from the caller and func keys. The stack and
+// the error chain are not restored, because logfmtFormatter does not write them.
+type Engine struct {
+	fieldMap         log.FieldMap
+	disableTimestamp bool
+	timestampFormat  string
+}
+
+// Parse parses the first line of data into entry and returns the number of consumed bytes.
+func (that *Engine) Parse(data []byte, entry *log.Entry) (int, error) {
+	line := data
+	n := len(data)
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+		n = i + 1
+	}
+
+	fields, err := parseLine(line)
+	if err != nil {
+		return n, err
+	}
+
+	if entry.Data == nil {
+		entry.Data = make(log.Fields, len(fields))
+	}
+	that.consume(fields, entry)
+	that.fieldMap.DecodePrefixFieldClashes(entry.Data)
+	if entry.HasCaller() {
+		that.fieldMap.DecodePrefixCallerClashes(entry.Data)
+	}
+	return n, nil
+}
+
+func (that *Engine) consume(fields log.Fields, entry *log.Entry) {
+	if !that.disableTimestamp {
+		key := that.fieldMap.Resolve(log.FieldKeyTime)
+		if t, ok := fields[key].(string); ok {
+			v, err := time.Parse(that.timestampFormat, t)
+			if err == nil {
+				entry.Time = v
+			}
+		}
+		delete(fields, key)
+	}
+
+	key := that.fieldMap.Resolve(log.FieldKeyMsg)
+	if v, ok := fields[key].(string); ok {
+		entry.Message = v
+		delete(fields, key)
+	}
+
+	key = that.fieldMap.Resolve(log.FieldKeyLevel)
+	if v, ok := fields[key].(string); ok {
+		level, _ := log.ParseLevel(v)
+		entry.Level = level
+		delete(fields, key)
+	}
+
+	key = that.fieldMap.Resolve(log.FieldKeyLoggerError)
+	if v, ok := fields[key].(string); ok {
+		entry.LogErr = v
+		delete(fields, key)
+	}
+
+	that.consumeCaller(fields, entry)
+
+	for k, v := range fields {
+		entry.Data[k] = v
+	}
+}
+
+// consumeCaller restores the caller written as "file:line" and the function.
+func (that *Engine) consumeCaller(fields log.Fields, entry *log.Entry) {
+	callerKey := that.fieldMap.Resolve(log.FieldKeyCaller)
+	funcKey := that.fieldMap.Resolve(log.FieldKeyFunc)
+	caller, ok := fields[callerKey].(string)
+	if !ok {
+		return
+	}
+	function, ok := fields[funcKey].(string)
+	if !ok {
+		return
+	}
+
+	i := strings.LastIndexByte(caller, ':')
+	if i < 0 {
+		return
+	}
+	line, err := strconv.Atoi(caller[i+1:])
+	if err != nil {
+		return
+	}
+
+	entry.Caller = &runtime.Frame{Function: function, File: caller[:i], Line: line}
+	delete(fields, callerKey)
+	delete(fields, funcKey)
+}
+
+// parseLine splits the line into pairs of key=value.
+// A key without value has an empty value.
+func parseLine(line []byte) (log.Fields, error) {
+	fields := make(log.Fields)
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' || line[i] == '\r' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' && line[i] != '\r' {
+			if line[i] == '"' {
+				return nil, fmt.Errorf("%w: unexpected quote in key at %d", ErrInvalidSyntax, i)
+			}
+			i++
+		}
+		key := string(line[start:i])
+		if i == len(line) || line[i] != '=' {
+			fields[key] = ""
+			continue
+		}
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end, err := scanQuoted(line, i)
+			if err != nil {
+				return nil, err
+			}
+			value, err := strconv.Unquote(string(line[i:end]))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid quoted value of %q, %v", ErrInvalidSyntax, key, err)
+			}
+			fields[key] = value
+			i = end
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' && line[i] != '\r' {
+			i++
+		}
+		if value := string(line[start:i]); value == "null" {
+			fields[key] = nil
+		} else {
+			fields[key] = value
+		}
+	}
+	return fields, nil
+}
+
+// scanQuoted returns the position after the closing quote of the value starting at i.
+func scanQuoted(line []byte, i int) (int, error) {
+	for j := i + 1; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated quoted value at %d", ErrInvalidSyntax, i)
+}
+
+var (
+	ErrInvalidSyntax = errors.New("invalid logfmt syntax")
+)
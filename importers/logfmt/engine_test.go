@@ -0,0 +1,85 @@
+package logfmtImporter
+
+import (
+	"github.com/adverax/log"
+	logfmtFormatter "github.com/adverax/log/formatters/logfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestParser(t *testing.T) {
+	parser, err := NewBuilder().Build()
+	require.NoError(t, err)
+
+	data := []byte(`time=2024-11-29T11:33:22Z level=warn msg="Hello, \"World\"\n" fields.msg=clash empty="" none=null flag key=value` + "\nnext line")
+	entry := log.NewEntry(nil)
+	n, err := parser.Parse(data, entry)
+	require.NoError(t, err)
+	assert.Equal(t, len(data)-len("next line"), n)
+	assert.Equal(t, log.WarnLevel, entry.Level)
+	assert.Equal(t, "Hello, \"World\"\n", entry.Message)
+	assert.Equal(t, time.Date(2024, 11, 29, 11, 33, 22, 0, time.UTC), entry.Time)
+	assert.Equal(t, log.Fields{"msg": "clash", "empty": "", "none": nil, "flag": "", "key": "value"}, entry.Data)
+}
+
+func TestParserErrors(t *testing.T) {
+	type Test struct {
+		name string
+		line string
+	}
+
+	tests := []Test{
+		{name: "unterminated quote", line: `msg="Hello`},
+		{name: "quote in key", line: `m"sg=Hello`},
+		{name: "invalid escape", line: `msg="\q"`},
+	}
+
+	parser, err := NewBuilder().Build()
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parser.Parse([]byte(test.line), log.NewEntry(nil))
+			require.ErrorIs(t, err, ErrInvalidSyntax)
+		})
+	}
+}
+
+func TestParserRoundTrip(t *testing.T) {
+	fieldMap := log.FieldMap{log.FieldKeyMsg: "message"}
+	formatter, err := logfmtFormatter.NewBuilder().WithFieldMap(fieldMap).Build()
+	require.NoError(t, err)
+	parser, err := NewBuilder().WithFieldMap(fieldMap).Build()
+	require.NoError(t, err)
+
+	source := &log.Entry{
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   log.ErrorLevel,
+		Message: "multi\nline \"message\" with = and \\ \x01 Привет",
+		LogErr:  "can not add field \"fn\"",
+		Data: log.Fields{
+			"message": "clash",
+			"caller":  "clash",
+			"empty":   "",
+			"null":    "null",
+			"path":    "/api",
+		},
+		Caller: &runtime.Frame{Function: "main.main", File: "/app/main.go", Line: 12},
+	}
+	data, err := formatter.Format(source)
+	require.NoError(t, err)
+
+	entry := log.NewEntry(nil)
+	n, err := parser.Parse(data, entry)
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, source.Time, entry.Time)
+	assert.Equal(t, source.Level, entry.Level)
+	assert.Equal(t, source.Message, entry.Message)
+	assert.Equal(t, source.LogErr, entry.LogErr)
+	assert.Equal(t, source.Caller, entry.Caller)
+	assert.Equal(t, source.Data, entry.Data)
+}
@@ -0,0 +1,27 @@
+package otelIntegration
+
+import (
+	"context"
+	"github.com/adverax/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewExtractor returns extractor of the OpenTelemetry span context.
+// It fills trace_id, span_id and trace_flags fields of entries,
+// that are logged with a context carrying a valid span.
+func NewExtractor() log.ContextExtractor {
+	return log.ContextExtractorFunc(extract)
+}
+
+func extract(ctx context.Context) log.Fields {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return log.Fields{
+		log.FieldKeyTraceID:    sc.TraceID().String(),
+		log.FieldKeySpanID:     sc.SpanID().String(),
+		log.FieldKeyTraceFlags: sc.TraceFlags().String(),
+	}
+}
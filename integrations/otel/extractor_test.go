@@ -0,0 +1,27 @@
+package otelIntegration
+
+import (
+	"context"
+	"github.com/adverax/log"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestExtractor(t *testing.T) {
+	extractor := NewExtractor()
+	assert.Nil(t, extractor.Extract(context.Background()))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	assert.Equal(t, log.Fields{
+		log.FieldKeyTraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		log.FieldKeySpanID:     "00f067aa0ba902b7",
+		log.FieldKeyTraceFlags: "01",
+	}, extractor.Extract(ctx))
+}
@@ -0,0 +1,201 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LevelRegistry holds level rules keyed by logger name and applies them
+// to the attached loggers at runtime. Named children of attached loggers
+// are attached automatically.
+//
+// Patterns of rules are matched by MatchLoggerName. The most specific rule wins.
+type LevelRegistry struct {
+	mu    sync.RWMutex
+	rules map[string]Level
+	logs  []*Log
+}
+
+func NewLevelRegistry() *LevelRegistry {
+	return &LevelRegistry{
+		rules: make(map[string]Level),
+	}
+}
+
+// Set adds or replaces the rule for the pattern.
+func (that *LevelRegistry) Set(pattern string, level Level) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	that.rules[pattern] = level
+	that.apply()
+}
+
+// Unset removes the rule for the pattern.
+func (that *LevelRegistry) Unset(pattern string) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	delete(that.rules, pattern)
+	that.apply()
+}
+
+// Reset removes all rules.
+func (that *LevelRegistry) Reset() {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	that.rules = make(map[string]Level)
+	that.apply()
+}
+
+// Parse applies rules from the spec like "warn,db=debug,http.*=trace".
+// A level without pattern is a rule for "*".
+// Rules are applied only when the whole spec is valid.
+func (that *LevelRegistry) Parse(spec string) error {
+	rules, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	for pattern, level := range rules {
+		that.rules[pattern] = level
+	}
+	that.apply()
+	return nil
+}
+
+// Lookup returns the level of the most specific rule matching the name.
+func (that *LevelRegistry) Lookup(name string) (Level, bool) {
+	that.mu.RLock()
+	defer that.mu.RUnlock()
+
+	return that.lookup(name)
+}
+
+// Rules returns a copy of all rules.
+func (that *LevelRegistry) Rules() map[string]Level {
+	that.mu.RLock()
+	defer that.mu.RUnlock()
+
+	rules := make(map[string]Level, len(that.rules))
+	for k, v := range that.rules {
+		rules[k] = v
+	}
+	return rules
+}
+
+// Loggers returns attached loggers, whose names match the pattern.
+func (that *LevelRegistry) Loggers(pattern string) []*Log {
+	that.mu.RLock()
+	defer that.mu.RUnlock()
+
+	var logs []*Log
+	for _, log := range that.logs {
+		if MatchLoggerName(pattern, log.name) {
+			logs = append(logs, log)
+		}
+	}
+	return logs
+}
+
+// String returns rules in the format accepted by Parse.
+func (that *LevelRegistry) String() string {
+	rules := that.Rules()
+	items := make([]string, 0, len(rules))
+	for pattern, level := range rules {
+		items = append(items, pattern+"="+level.String())
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+func (that *LevelRegistry) attach(log *Log) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	that.logs = append(that.logs, log)
+	log.setLevelOverride(that.lookup(log.name))
+}
+
+func (that *LevelRegistry) detach(log *Log) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	for i, l := range that.logs {
+		if l == log {
+			last := len(that.logs) - 1
+			copy(that.logs[i:], that.logs[i+1:])
+			that.logs[last] = nil
+			that.logs = that.logs[:last]
+			return
+		}
+	}
+}
+
+func (that *LevelRegistry) apply() {
+	for _, log := range that.logs {
+		log.setLevelOverride(that.lookup(log.name))
+	}
+}
+
+func (that *LevelRegistry) lookup(name string) (Level, bool) {
+	if level, ok := that.rules[name]; ok {
+		return level, true
+	}
+
+	for parent := name; ; {
+		i := strings.LastIndexByte(parent, '.')
+		if i < 0 {
+			break
+		}
+		parent = parent[:i]
+		if level, ok := that.rules[parent+".*"]; ok {
+			return level, true
+		}
+		if level, ok := that.rules[parent]; ok {
+			return level, true
+		}
+	}
+
+	level, ok := that.rules["*"]
+	return level, ok
+}
+
+func parseLevelSpec(spec string) (map[string]Level, error) {
+	rules := make(map[string]Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		pattern, name, found := strings.Cut(item, "=")
+		if !found {
+			pattern, name = "*", item
+		}
+
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLevelSpec, item)
+		}
+
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLevelSpec, item)
+		}
+
+		rules[pattern] = level
+	}
+	return rules, nil
+}
+
+var (
+	ErrInvalidLevelSpec = errors.New("invalid level spec")
+)
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLevelRegistry(t *testing.T) {
+	registry := NewLevelRegistry()
+	require.NoError(t, registry.Parse("warn, db=debug, http.*=trace"))
+	assert.ErrorIs(t, registry.Parse("db=loud"), ErrInvalidLevelSpec)
+	assert.Equal(t, "*=warn,db=debug,http.*=trace", registry.String())
+
+	type Test struct {
+		name     string
+		expected Level
+	}
+
+	tests := []Test{
+		{name: "", expected: WarnLevel},
+		{name: "db", expected: DebugLevel},
+		{name: "db.pool", expected: DebugLevel},
+		{name: "http", expected: WarnLevel},
+		{name: "http.client", expected: TraceLevel},
+		{name: "dbx", expected: WarnLevel},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			level, ok := registry.Lookup(test.name)
+			assert.True(t, ok)
+			assert.Equal(t, test.expected, level)
+		})
+	}
+}
+
+func TestLogLevelOverride(t *testing.T) {
+	registry := NewLevelRegistry()
+	logger, err := NewBuilder().
+		WithName("db.pool").
+		WithLevel(InfoLevel).
+		WithLevelRegistry(registry).
+		WithExporter(new(dummyExporter)).
+		Build()
+	require.NoError(t, err)
+
+	assert.False(t, logger.IsLevelEnabled(DebugLevel))
+
+	logger.SetLevel(DebugLevel)
+	assert.True(t, logger.IsLevelEnabled(DebugLevel))
+
+	registry.Set("db", ErrorLevel)
+	assert.Equal(t, ErrorLevel, logger.GetLevel())
+	assert.False(t, logger.IsLevelEnabled(WarnLevel))
+
+	registry.Unset("db")
+	assert.Equal(t, DebugLevel, logger.GetLevel())
+}
+
+func TestLevelRegistryDetach(t *testing.T) {
+	registry := NewLevelRegistry()
+	root, err := NewBuilder().
+		WithName("app").
+		WithLevelRegistry(registry).
+		WithExporter(new(dummyExporter)).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	db := root.Named("db")
+	pool := db.Named("pool")
+	http := root.Named("http")
+	assert.Equal(t, []*Log{root, db, pool, http}, registry.Loggers("*"))
+
+	require.NoError(t, db.Close(ctx))
+	assert.Equal(t, []*Log{root, http}, registry.Loggers("*"))
+	assert.NotSame(t, db, root.Named("db"))
+
+	require.NoError(t, root.Close(ctx))
+	assert.Empty(t, registry.Loggers("*"))
+}
@@ -3,18 +3,43 @@ package log
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// ExitFunc terminates the process with the given status code.
+type ExitFunc func(code int)
+
+// noLevelOverride means that the level registry has no rule for the logger.
+const noLevelOverride = -1
+
 type Log struct {
+	name     string
+	parent   *Log
+	children map[string]*Log
 	exporter Exporter
-	level    Level
-	mu       sync.Mutex
-	hooks    *Hooks
-	peaces   *pool[Piece]
-	entries  *pool[Entry]
-	buffers  *pool[bytes.Buffer]
+	level    atomic.Uint32
+	// ownLevel reports whether the level is set for the named child,
+	// otherwise it inherits the level of the parent.
+	ownLevel     atomic.Bool
+	override     atomic.Int32
+	levels       *LevelRegistry
+	reportCaller bool
+	callerSkip   int
+	captureStack bool
+	stackLevel   Level
+	extractors   []ContextExtractor
+	mu           sync.Mutex
+	exitFunc     ExitFunc
+	exitHandlers []func()
+	hooks        *Hooks
+	peaces       *pool[Piece]
+	entries      *pool[Entry]
+	buffers      *pool[bytes.Buffer]
 }
 
 func (that *Log) WithField(key string, value interface{}) LoggerEntry {
@@ -132,6 +157,8 @@ func (that *Log) LogFn(ctx context.Context, level Level, fn LogFunction) {
 
 		entry.Level = level
 		fn(ctx, entry)
+	} else if level == FatalLevel {
+		that.Exit(ctx, 1)
 	}
 }
 
@@ -141,6 +168,8 @@ func (that *Log) Logf(ctx context.Context, level Level, format string, args ...i
 		defer that.freeEntry(entry)
 
 		entry.Logf(ctx, level, format, args...)
+	} else if level == FatalLevel {
+		that.Exit(ctx, 1)
 	}
 }
 
@@ -150,17 +179,221 @@ func (that *Log) Log(ctx context.Context, level Level, args ...interface{}) {
 		defer that.freeEntry(entry)
 
 		entry.Log(ctx, level, args...)
+	} else if level == FatalLevel {
+		that.Exit(ctx, 1)
+	}
+}
+
+func (that *Log) PanicFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, PanicLevel, msg, fields...)
+}
+
+func (that *Log) FatalFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, FatalLevel, msg, fields...)
+}
+
+func (that *Log) ErrorFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, ErrorLevel, msg, fields...)
+}
+
+func (that *Log) WarningFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, WarnLevel, msg, fields...)
+}
+
+func (that *Log) InfoFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, InfoLevel, msg, fields...)
+}
+
+func (that *Log) DebugFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, DebugLevel, msg, fields...)
+}
+
+func (that *Log) TraceFields(ctx context.Context, msg string, fields ...Field) {
+	that.LogFields(ctx, TraceLevel, msg, fields...)
+}
+
+// LogFields logs the message with typed fields, that are not boxed into Fields.
+func (that *Log) LogFields(ctx context.Context, level Level, msg string, fields ...Field) {
+	if that.IsLevelEnabled(level) {
+		entry := that.newEntry()
+		defer that.freeEntry(entry)
+
+		entry.LogFields(ctx, level, msg, fields...)
+	} else if level == FatalLevel {
+		that.Exit(ctx, 1)
 	}
 }
 
 func (that *Log) IsLevelEnabled(level Level) bool {
-	return that.level >= level
+	return that.GetLevel() >= level
+}
+
+// Name returns the name, that is used to find the level in the level registry.
+func (that *Log) Name() string {
+	return that.name
+}
+
+// GetLevel returns the effective level of the logger.
+// A rule of the level registry takes precedence over the level of the logger.
+func (that *Log) GetLevel() Level {
+	if override := that.override.Load(); override != noLevelOverride {
+		return Level(override)
+	}
+	return that.BaseLevel()
+}
+
+// BaseLevel returns the level of the logger without the rule of the level registry.
+// A named child without own level returns the level of its parent.
+func (that *Log) BaseLevel() Level {
+	if that.InheritsLevel() {
+		return that.parent.BaseLevel()
+	}
+	return Level(that.level.Load())
+}
+
+// LevelOverride returns the level of the rule of the level registry, that is applied to the logger.
+func (that *Log) LevelOverride() (Level, bool) {
+	if override := that.override.Load(); override != noLevelOverride {
+		return Level(override), true
+	}
+	return 0, false
+}
+
+// SetLevel changes the level of the logger at runtime.
+// The named child stops to inherit the level of its parent.
+func (that *Log) SetLevel(level Level) {
+	that.level.Store(uint32(level))
+	that.ownLevel.Store(true)
+}
+
+// ResetLevel makes the named child to inherit the level of its parent again.
+func (that *Log) ResetLevel() {
+	that.ownLevel.Store(false)
+}
+
+// InheritsLevel reports whether the named child inherits the level of its parent.
+func (that *Log) InheritsLevel() bool {
+	return that.parent != nil && !that.ownLevel.Load()
+}
+
+func (that *Log) setLevelOverride(level Level, ok bool) {
+	if ok {
+		that.override.Store(int32(level))
+	} else {
+		that.override.Store(noLevelOverride)
+	}
 }
 
 func (that *Log) AddHook(levels []Level, hook Hook) {
 	that.hooks.Add(levels, hook)
 }
 
+// Flush flushes hooks and exporter of the logger.
+func (that *Log) Flush(ctx context.Context) error {
+	if that.parent != nil {
+		return errors.Join(
+			that.hooks.Flush(ctx),
+			that.parent.Flush(ctx),
+		)
+	}
+
+	return errors.Join(
+		that.hooks.Flush(ctx),
+		Flush(ctx, that.exporter),
+	)
+}
+
+// Close closes hooks and exporter of the logger.
+// Hooks are closed first, because they may still write entries.
+// A named child closes only its own hooks, because the exporter
+// is owned by the root logger.
+// The logger and its named children are detached from the level registry,
+// so short-lived loggers are not retained by it.
+func (that *Log) Close(ctx context.Context) error {
+	that.detach()
+
+	if that.parent != nil {
+		that.parent.mu.Lock()
+		if that.parent.children[that.name] == that {
+			delete(that.parent.children, that.name)
+		}
+		that.parent.mu.Unlock()
+
+		return that.hooks.Close(ctx)
+	}
+
+	return errors.Join(
+		that.hooks.Close(ctx),
+		Close(ctx, that.exporter),
+	)
+}
+
+// detach removes the logger and its named children from the level registry,
+// so closed loggers are not retained by it.
+func (that *Log) detach() {
+	that.mu.Lock()
+	children := that.children
+	that.children = nil
+	that.mu.Unlock()
+
+	for _, child := range children {
+		child.detach()
+	}
+	if that.levels != nil {
+		that.levels.detach(that)
+	}
+}
+
+// RegisterExitHandler appends a handler, that is called before the process
+// is terminated by a fatal entry. Handlers are called in order of registration.
+func (that *Log) RegisterExitHandler(handler func()) {
+	if that.parent != nil {
+		that.parent.RegisterExitHandler(handler)
+		return
+	}
+
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	that.exitHandlers = append(that.exitHandlers, handler)
+}
+
+// Exit flushes the logger, runs exit handlers and terminates the process
+// by the exit function of the logger.
+func (that *Log) Exit(ctx context.Context, code int) {
+	if that.parent != nil {
+		if err := that.hooks.Flush(context.WithoutCancel(ctx)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to flush log: %v\n", err)
+		}
+		that.parent.Exit(ctx, code)
+		return
+	}
+
+	if err := that.Flush(context.WithoutCancel(ctx)); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to flush log: %v\n", err)
+	}
+
+	that.mu.Lock()
+	handlers := append([]func(){}, that.exitHandlers...)
+	that.mu.Unlock()
+
+	for _, handler := range handlers {
+		runExitHandler(handler)
+	}
+
+	that.exitFunc(code)
+}
+
+func runExitHandler(handler func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			fmt.Fprintf(os.Stderr, "Exit handler panicked: %v\n", err)
+		}
+	}()
+
+	handler()
+}
+
 func (that *Log) newEntry() *Entry {
 	entry := that.entries.Get()
 	entry.clear()
@@ -169,7 +402,10 @@ func (that *Log) newEntry() *Entry {
 }
 
 func (that *Log) freeEntry(entry *Entry) {
-	entry.Data = map[string]interface{}{}
+	entry.Data = nil
+	entry.Bound = nil
+	clear(entry.TypedFields)
+	entry.TypedFields = entry.TypedFields[:0]
 	that.entries.Put(entry)
 }
 
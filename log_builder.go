@@ -3,6 +3,7 @@ package log
 import (
 	"bytes"
 	"errors"
+	"os"
 )
 
 type Builder struct {
@@ -10,19 +11,37 @@ type Builder struct {
 }
 
 func NewBuilder() *Builder {
+	l := &Log{
+		exitFunc:   os.Exit,
+		extractors: []ContextExtractor{fieldsExtractor},
+		hooks:      NewHooks(),
+		peaces:     newPool[Piece](),
+		entries:    newPool[Entry](),
+		buffers:    newPool[bytes.Buffer](),
+	}
+	l.SetLevel(InfoLevel)
+	l.setLevelOverride(0, false)
+
 	return &Builder{
-		log: &Log{
-			level:   InfoLevel,
-			hooks:   NewHooks(),
-			peaces:  newPool[Piece](),
-			entries: newPool[Entry](),
-			buffers: newPool[bytes.Buffer](),
-		},
+		log: l,
 	}
 }
 
 func (that *Builder) WithLevel(level Level) *Builder {
-	that.log.level = level
+	that.log.SetLevel(level)
+	return that
+}
+
+// WithName sets the name of the logger, e.g. "db" or "http.client".
+func (that *Builder) WithName(name string) *Builder {
+	that.log.name = name
+	return that
+}
+
+// WithLevelRegistry attaches the logger to the registry,
+// so that the level can be overridden by name at runtime.
+func (that *Builder) WithLevelRegistry(registry *LevelRegistry) *Builder {
+	that.log.levels = registry
 	return that
 }
 
@@ -31,6 +50,46 @@ func (that *Builder) WithExporter(exporter Exporter) *Builder {
 	return that
 }
 
+// WithReportCaller enables recording of the calling frame into Entry.Caller.
+func (that *Builder) WithReportCaller(reportCaller bool) *Builder {
+	that.log.reportCaller = reportCaller
+	return that
+}
+
+// WithCallerSkip sets the number of extra frames to skip above the logger,
+// e.g. for own wrapper helpers.
+func (that *Builder) WithCallerSkip(skip int) *Builder {
+	that.log.callerSkip = skip
+	return that
+}
+
+// WithStackTrace enables capturing of stack trace into Entry.Stack for entries
+// with the level or more severe. The stack carried by the error of the entry
+// is preferred over the stack of the logging call.
+func (that *Builder) WithStackTrace(level Level) *Builder {
+	that.log.captureStack = true
+	that.log.stackLevel = level
+	return that
+}
+
+// WithContextExtractor adds extractor of fields from the context of logging call.
+// Fields attached by ContextWithFields are always extracted.
+func (that *Builder) WithContextExtractor(extractor ContextExtractor) *Builder {
+	that.log.extractors = append(that.log.extractors, extractor)
+	return that
+}
+
+// WithExitFunc sets the function, that terminates the process after a fatal entry.
+func (that *Builder) WithExitFunc(exitFunc ExitFunc) *Builder {
+	that.log.exitFunc = exitFunc
+	return that
+}
+
+func (that *Builder) WithExitHandler(handler func()) *Builder {
+	that.log.RegisterExitHandler(handler)
+	return that
+}
+
 func (that *Builder) WithHook(hook Hook) *Builder {
 	that.log.AddHook(Levels.Keys(), hook)
 	return that
@@ -50,6 +109,10 @@ func (that *Builder) Build() (*Log, error) {
 	if err := that.checkRequiredFields(); err != nil {
 		return nil, err
 	}
+
+	if that.log.levels != nil {
+		that.log.levels.attach(that.log)
+	}
 	return that.log, nil
 }
 
@@ -57,12 +120,16 @@ func (that *Builder) checkRequiredFields() error {
 	if that.log.exporter == nil {
 		return ErrRequiredFieldExporter
 	}
+	if that.log.exitFunc == nil {
+		return ErrRequiredFieldExitFunc
+	}
 
 	return nil
 }
 
 var (
 	ErrRequiredFieldExporter = errors.New("exporter is required")
+	ErrRequiredFieldExitFunc = errors.New("exit func is required")
 )
 
 func NewDummyLogger() *Log {
@@ -43,3 +43,115 @@ func TestLogger(t *testing.T) {
 	assert.Equal(t, "Hello, World2!", exporter.entry.Message)
 	assert.Equal(t, ErrorLevel, exporter.entry.Level)
 }
+
+type closingHook struct {
+	closed int
+}
+
+func (that *closingHook) Fire(ctx context.Context, entry *Entry) error {
+	return nil
+}
+
+func (that *closingHook) Close(ctx context.Context) error {
+	that.closed++
+	return nil
+}
+
+type closingExporter struct {
+	myExporter
+	flushed int
+	closed  int
+}
+
+func (that *closingExporter) Flush(ctx context.Context) error {
+	that.flushed++
+	return nil
+}
+
+func (that *closingExporter) Close(ctx context.Context) error {
+	that.closed++
+	return fmt.Errorf("closed")
+}
+
+func TestLogClose(t *testing.T) {
+	exporter := &closingExporter{}
+	hook := &closingHook{}
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		WithHookForLevels(hook, []Level{ErrorLevel, WarnLevel}).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, logger.Flush(ctx))
+	assert.EqualError(t, logger.Close(ctx), "closed")
+	assert.Equal(t, 1, exporter.flushed)
+	assert.Equal(t, 1, exporter.closed)
+	assert.Equal(t, 1, hook.closed)
+}
+
+func TestHooksCloseOnce(t *testing.T) {
+	hook := &closingHook{}
+	var fired int
+	fn := HookFunc(func(ctx context.Context, entry *Entry) error {
+		fired++
+		return nil
+	})
+
+	hooks := NewHooks()
+	hooks.Add([]Level{ErrorLevel, WarnLevel}, hook)
+	hooks.Add([]Level{InfoLevel}, hook)
+	hooks.Add([]Level{InfoLevel}, fn)
+	hooks.Add([]Level{InfoLevel}, fn)
+
+	ctx := context.Background()
+	require.NoError(t, hooks.Fire(ctx, InfoLevel, &Entry{}))
+	require.NoError(t, hooks.Close(ctx))
+	assert.Equal(t, 1, hook.closed)
+	assert.Equal(t, 2, fired)
+}
+
+func TestLogFatal(t *testing.T) {
+	exporter := &closingExporter{}
+	var calls []string
+
+	logger, err := NewBuilder().
+		WithLevel(PanicLevel).
+		WithExporter(exporter).
+		WithExitHandler(func() {
+			calls = append(calls, "handler")
+		}).
+		WithExitFunc(func(code int) {
+			calls = append(calls, fmt.Sprintf("exit %d", code))
+		}).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	logger.Fatal(ctx, "skipped, but exits")
+	assert.Nil(t, exporter.entry)
+
+	logger.RegisterExitHandler(func() {
+		panic("ignored")
+	})
+	logger.WithField("key", "value").Fatal(ctx, "skipped, but exits")
+
+	assert.Equal(t, []string{"handler", "exit 1", "handler", "exit 1"}, calls)
+	assert.Equal(t, 2, exporter.flushed)
+
+	calls = nil
+	logger, err = NewBuilder().
+		WithLevel(FatalLevel).
+		WithExporter(exporter).
+		WithExitFunc(func(code int) {
+			calls = append(calls, fmt.Sprintf("exit %d", code))
+		}).
+		Build()
+	require.NoError(t, err)
+
+	logger.Fatalf(ctx, "exit %d", 1)
+	require.NotNil(t, exporter.entry)
+	assert.Equal(t, "exit 1", exporter.entry.Message)
+	assert.Equal(t, []string{"exit 1"}, calls)
+}
@@ -3,6 +3,7 @@ package log
 import (
 	"context"
 	"github.com/adverax/enums"
+	"strings"
 )
 
 type Level uint8
@@ -44,6 +45,18 @@ var Levels = enums.New[Level](
 	},
 )
 
+// maxLevelLength is the length of the longest name of level.
+const maxLevelLength = 5
+
+// ParseLevel converts the name of level to Level. The name is case-insensitive.
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		return WarnLevel, nil
+	}
+	return Levels.Encode(name)
+}
+
 type LogFunction func(ctx context.Context, logger LoggerEntry)
 
 type Fields map[string]interface{}
@@ -113,6 +126,25 @@ type LoggerEntry interface {
 	Trace(ctx context.Context, args ...interface{})
 }
 
+// FieldsLogger logs messages with typed fields, that are not boxed into Fields.
+// It is implemented by *Log, *Entry and *BoundLogger.
+type FieldsLogger interface {
+	PanicFields(ctx context.Context, msg string, fields ...Field)
+	FatalFields(ctx context.Context, msg string, fields ...Field)
+	ErrorFields(ctx context.Context, msg string, fields ...Field)
+	WarningFields(ctx context.Context, msg string, fields ...Field)
+	InfoFields(ctx context.Context, msg string, fields ...Field)
+	DebugFields(ctx context.Context, msg string, fields ...Field)
+	TraceFields(ctx context.Context, msg string, fields ...Field)
+	LogFields(ctx context.Context, level Level, msg string, fields ...Field)
+}
+
+var (
+	_ FieldsLogger = (*Log)(nil)
+	_ FieldsLogger = (*Entry)(nil)
+	_ FieldsLogger = (*BoundLogger)(nil)
+)
+
 type Logger interface {
 	LoggerEntry
 
@@ -0,0 +1,66 @@
+package log
+
+import "strings"
+
+// Named returns the child logger with the name appended to the name of
+// the logger by a dot, e.g. Named("db").Named("pool") is "db.pool".
+// The child shares the exporter and hooks of the parent, inherits its
+// configuration and level, and adds the "logger" field to entries.
+// The level of the parent is inherited, until the child gets own level by SetLevel.
+// Own level and extra hooks of the child do not affect the parent.
+// Repeated calls with the same name return the same child.
+func (that *Log) Named(name string) *Log {
+	if that.name != "" {
+		name = that.name + "." + name
+	}
+
+	that.mu.Lock()
+	defer that.mu.Unlock()
+
+	if child, ok := that.children[name]; ok {
+		return child
+	}
+
+	child := &Log{
+		name:         name,
+		parent:       that,
+		exporter:     that.exporter,
+		levels:       that.levels,
+		reportCaller: that.reportCaller,
+		callerSkip:   that.callerSkip,
+		captureStack: that.captureStack,
+		stackLevel:   that.stackLevel,
+		extractors:   that.extractors,
+		exitFunc:     that.exitFunc,
+		hooks:        that.hooks.Child(),
+		peaces:       that.peaces,
+		entries:      that.entries,
+		buffers:      that.buffers,
+	}
+	child.setLevelOverride(0, false)
+
+	if that.children == nil {
+		that.children = make(map[string]*Log)
+	}
+	that.children[name] = child
+
+	if child.levels != nil {
+		child.levels.attach(child)
+	}
+	return child
+}
+
+// MatchLoggerName reports whether the name of logger matches the pattern:
+//   - "db" matches "db" and all its descendants ("db.pool", ...);
+//   - "db.*" matches descendants of "db" only;
+//   - "*" matches every logger.
+func MatchLoggerName(pattern, name string) bool {
+	switch {
+	case pattern == "*":
+		return true
+	case strings.HasSuffix(pattern, ".*"):
+		return strings.HasPrefix(name, pattern[:len(pattern)-1])
+	default:
+		return name == pattern || strings.HasPrefix(name, pattern+".")
+	}
+}
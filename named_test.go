@@ -0,0 +1,87 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	exporter := &myExporter{}
+	registry := NewLevelRegistry()
+	var fired []string
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		WithLevelRegistry(registry).
+		WithHook(HookFunc(func(ctx context.Context, entry *Entry) error {
+			fired = append(fired, "root")
+			return nil
+		})).
+		Build()
+	require.NoError(t, err)
+
+	db := logger.Named("db")
+	pool := db.Named("pool")
+	assert.Same(t, pool, logger.Named("db").Named("pool"))
+	assert.Equal(t, "db.pool", pool.Name())
+
+	pool.AddHook(Levels.Keys(), HookFunc(func(ctx context.Context, entry *Entry) error {
+		fired = append(fired, "pool")
+		return nil
+	}))
+
+	ctx := context.Background()
+	pool.WithField("key", "value").Info(ctx, "Hello, World!")
+	assert.Equal(t, Fields{FieldKeyLogger: "db.pool", "key": "value"}, exporter.entry.Data)
+	assert.Equal(t, []string{"root", "pool"}, fired)
+
+	fired = nil
+	db.Info(ctx, "Hello, World!")
+	assert.Equal(t, []string{"root"}, fired)
+
+	pool.SetLevel(DebugLevel)
+	assert.True(t, pool.IsLevelEnabled(DebugLevel))
+	assert.False(t, db.IsLevelEnabled(DebugLevel))
+
+	require.NoError(t, registry.Parse("db.*=trace"))
+	assert.Equal(t, TraceLevel, pool.GetLevel())
+	assert.Equal(t, InfoLevel, db.GetLevel())
+	assert.Equal(t, []*Log{pool}, registry.Loggers("db.*"))
+	assert.Equal(t, []*Log{db, pool}, registry.Loggers("db"))
+}
+
+func TestMatchLoggerName(t *testing.T) {
+	assert.True(t, MatchLoggerName("*", "db"))
+	assert.True(t, MatchLoggerName("db", "db"))
+	assert.True(t, MatchLoggerName("db", "db.pool"))
+	assert.False(t, MatchLoggerName("db", "dbx"))
+	assert.False(t, MatchLoggerName("db.*", "db"))
+	assert.True(t, MatchLoggerName("db.*", "db.pool.conn"))
+}
+
+func TestNamedInheritsLevel(t *testing.T) {
+	logger, err := NewBuilder().
+		WithLevel(InfoLevel).
+		WithExporter(new(dummyExporter)).
+		Build()
+	require.NoError(t, err)
+
+	db := logger.Named("db")
+	pool := db.Named("pool")
+	api := logger.Named("api")
+
+	logger.SetLevel(DebugLevel)
+	assert.Equal(t, DebugLevel, db.GetLevel())
+	assert.Equal(t, DebugLevel, pool.GetLevel())
+
+	db.SetLevel(WarnLevel)
+	logger.SetLevel(TraceLevel)
+	assert.Equal(t, WarnLevel, db.GetLevel())
+	assert.Equal(t, WarnLevel, pool.GetLevel())
+	assert.Equal(t, TraceLevel, api.GetLevel())
+
+	db.ResetLevel()
+	assert.Equal(t, TraceLevel, pool.GetLevel())
+}
@@ -0,0 +1,175 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler that writes records through Log,
+// so that hooks, formatters and exporters of the logger are applied.
+type SlogHandler struct {
+	logger *Log
+	goas   []slogGroupOrAttrs
+}
+
+type slogGroupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func NewSlogHandler(logger *Log) *SlogHandler {
+	return &SlogHandler{
+		logger: logger,
+	}
+}
+
+func (that *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return that.logger.IsLevelEnabled(LevelFromSlog(level))
+}
+
+func (that *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	level := LevelFromSlog(record.Level)
+	if !that.logger.IsLevelEnabled(level) {
+		return nil
+	}
+
+	entry := that.logger.newEntry()
+	defer that.logger.freeEntry(entry)
+
+	entry.Time = record.Time
+	entry.Data = that.makeData(record)
+	if that.logger.reportCaller && record.PC != 0 {
+		entry.Caller = getCallerByPC(record.PC)
+	}
+	entry.log(ctx, level, "", record.Message)
+	return nil
+}
+
+func (that *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return that
+	}
+	return that.with(slogGroupOrAttrs{attrs: attrs})
+}
+
+func (that *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return that
+	}
+	return that.with(slogGroupOrAttrs{group: name})
+}
+
+func (that *SlogHandler) with(goa slogGroupOrAttrs) *SlogHandler {
+	goas := make([]slogGroupOrAttrs, len(that.goas), len(that.goas)+1)
+	copy(goas, that.goas)
+	return &SlogHandler{
+		logger: that.logger,
+		goas:   append(goas, goa),
+	}
+}
+
+func (that *SlogHandler) makeData(record slog.Record) Fields {
+	data := make(Fields, len(that.goas)+record.NumAttrs())
+	current := data
+	for _, goa := range that.goas {
+		if goa.group != "" {
+			group := make(Fields)
+			current[goa.group] = group
+			current = group
+			continue
+		}
+		for _, attr := range goa.attrs {
+			appendSlogAttr(current, attr)
+		}
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		appendSlogAttr(current, attr)
+		return true
+	})
+
+	pruneEmptyGroups(data)
+	return data
+}
+
+func appendSlogAttr(data Fields, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		data[attr.Key] = attr.Value.Any()
+		return
+	}
+
+	attrs := attr.Value.Group()
+	if len(attrs) == 0 {
+		return
+	}
+
+	if attr.Key == "" {
+		for _, a := range attrs {
+			appendSlogAttr(data, a)
+		}
+		return
+	}
+
+	group, ok := data[attr.Key].(Fields)
+	if !ok {
+		group = make(Fields, len(attrs))
+		data[attr.Key] = group
+	}
+	for _, a := range attrs {
+		appendSlogAttr(group, a)
+	}
+}
+
+// pruneEmptyGroups removes groups without attributes, as required by slog.Handler.
+func pruneEmptyGroups(data Fields) bool {
+	for k, v := range data {
+		if group, ok := v.(Fields); ok && pruneEmptyGroups(group) {
+			delete(data, k)
+		}
+	}
+
+	return len(data) == 0
+}
+
+// LevelFromSlog maps slog level onto Level.
+// Levels above slog.LevelError are mapped to ErrorLevel, because
+// PanicLevel and FatalLevel have side effects that slog callers do not expect.
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// LevelToSlog maps Level onto slog level.
+func LevelToSlog(level Level) slog.Level {
+	switch level {
+	case TraceLevel:
+		return slog.LevelDebug - 4
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError + 8
+	}
+}
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	exporter := &myExporter{}
+
+	logger, err := NewBuilder().
+		WithLevel(InfoLevel).
+		WithExporter(exporter).
+		Build()
+	require.NoError(t, err)
+
+	err = errors.New("invalid value")
+	l := slog.New(NewSlogHandler(logger)).
+		With("service", "api").
+		WithGroup("request").
+		With("id", 7)
+
+	l.Debug("skipped")
+	assert.Nil(t, exporter.entry)
+
+	l.Warn("Hello, World!", "error", err, slog.Group("user", "name", "bob"), slog.Group("empty"))
+	require.NotNil(t, exporter.entry)
+	assert.Equal(t, WarnLevel, exporter.entry.Level)
+	assert.Equal(t, "Hello, World!", exporter.entry.Message)
+	assert.Equal(t, Fields{
+		"service": "api",
+		"request": Fields{
+			"id":    int64(7),
+			"error": err,
+			"user":  Fields{"name": "bob"},
+		},
+	}, exporter.entry.Data)
+}
+
+func TestLevelSlogMapping(t *testing.T) {
+	for _, level := range []Level{ErrorLevel, WarnLevel, InfoLevel, DebugLevel, TraceLevel} {
+		assert.Equal(t, level, LevelFromSlog(LevelToSlog(level)))
+	}
+	assert.Equal(t, ErrorLevel, LevelFromSlog(LevelToSlog(PanicLevel)))
+}
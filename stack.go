@@ -0,0 +1,124 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	maximumStackDepth = 64
+	maximumErrorDepth = 32
+)
+
+// StackTrace is a list of frames, the innermost first.
+type StackTrace []runtime.Frame
+
+func (that StackTrace) String() string {
+	var b strings.Builder
+	for _, frame := range that {
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// NewStackTrace converts program counters to the stack trace.
+func NewStackTrace(pcs []uintptr) StackTrace {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	stack := make(StackTrace, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame)
+		if !more {
+			return stack
+		}
+	}
+}
+
+// ErrorChainItem is an error of the chain built by ErrorChain.
+type ErrorChainItem struct {
+	// Depth is the level of wrapping, 0 for the outermost error.
+	Depth   int
+	Type    string
+	Message string
+}
+
+// ErrorChain flattens the tree of wrapped errors (errors.Unwrap and errors.Join)
+// in depth-first order.
+func ErrorChain(err error) []ErrorChainItem {
+	var items []ErrorChainItem
+	walkError(err, 0, func(err error, depth int) {
+		items = append(items, ErrorChainItem{
+			Depth:   depth,
+			Type:    fmt.Sprintf("%T", err),
+			Message: err.Error(),
+		})
+	})
+	return items
+}
+
+// StackOf returns the stack trace carried by the error chain.
+// When several errors carry a stack, the deepest one is used, because it is
+// the closest to the origin of the error. Errors may provide the stack by
+// a StackTrace method, that returns StackTrace or a slice of program counters
+// (like github.com/pkg/errors).
+func StackOf(err error) StackTrace {
+	var stack StackTrace
+	walkError(err, 0, func(err error, depth int) {
+		if s := stackOfError(err); s != nil {
+			stack = s
+		}
+	})
+	return stack
+}
+
+func walkError(err error, depth int, fn func(err error, depth int)) {
+	if err == nil || depth >= maximumErrorDepth {
+		return
+	}
+
+	fn(err, depth)
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			walkError(err, depth+1, fn)
+		}
+	case interface{ Unwrap() error }:
+		walkError(e.Unwrap(), depth+1, fn)
+	}
+}
+
+func stackOfError(err error) StackTrace {
+	if e, ok := err.(interface{ StackTrace() StackTrace }); ok {
+		return e.StackTrace()
+	}
+
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	frames := method.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+	return NewStackTrace(pcs)
+}
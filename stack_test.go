@@ -0,0 +1,80 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+)
+
+type testFrame uintptr
+
+type testStackError struct {
+	pcs []testFrame
+}
+
+func (that *testStackError) Error() string {
+	return "with stack"
+}
+
+func (that *testStackError) StackTrace() []testFrame {
+	return that.pcs
+}
+
+func newTestStackError() error {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(1, pcs)
+	err := &testStackError{}
+	for _, pc := range pcs[:n] {
+		err.pcs = append(err.pcs, testFrame(pc))
+	}
+	return err
+}
+
+func TestErrorChain(t *testing.T) {
+	a := errors.New("a")
+	b := errors.New("b")
+	err := fmt.Errorf("outer: %w", errors.Join(a, b))
+
+	chain := ErrorChain(err)
+	assert.Equal(t, []ErrorChainItem{
+		{Depth: 0, Type: "*fmt.wrapError", Message: "outer: a\nb"},
+		{Depth: 1, Type: "*errors.joinError", Message: "a\nb"},
+		{Depth: 2, Type: "*errors.errorString", Message: "a"},
+		{Depth: 2, Type: "*errors.errorString", Message: "b"},
+	}, chain)
+}
+
+func TestStackOf(t *testing.T) {
+	assert.Nil(t, StackOf(errors.New("plain")))
+
+	err := fmt.Errorf("wrapped: %w", newTestStackError())
+	stack := StackOf(err)
+	require.NotEmpty(t, stack)
+	assert.Equal(t, "github.com/adverax/log.newTestStackError", stack[0].Function)
+}
+
+func TestCaptureStack(t *testing.T) {
+	exporter := &myExporter{}
+
+	logger, err := NewBuilder().
+		WithExporter(exporter).
+		WithStackTrace(ErrorLevel).
+		Build()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	logger.Warning(ctx, "no stack")
+	assert.Nil(t, exporter.entry.Stack)
+
+	logger.Error(ctx, "stack of call")
+	require.NotEmpty(t, exporter.entry.Stack)
+	assert.Equal(t, "github.com/adverax/log.TestCaptureStack", exporter.entry.Stack[0].Function)
+
+	logger.WithError(newTestStackError()).Error(ctx, "stack of error")
+	require.NotEmpty(t, exporter.entry.Stack)
+	assert.Equal(t, "github.com/adverax/log.newTestStackError", exporter.entry.Stack[0].Function)
+}